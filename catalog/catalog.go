@@ -0,0 +1,157 @@
+// Package catalog localizes error messages from JSON or YAML catalogs
+// keyed by language and errorx code.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ice-coldbell/errorx"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog holds localized message templates keyed by language and error
+// code. Templates reference the error's data with named parameters such as
+// "user {user_id} not found".
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback language.Tag
+	messages map[language.Tag]map[string]string
+}
+
+// Default is used by Localize.
+var Default = New(language.English)
+
+func New(fallback language.Tag) *Catalog {
+	return &Catalog{
+		fallback: fallback,
+		messages: make(map[language.Tag]map[string]string),
+	}
+}
+
+func (c *Catalog) Set(tag language.Tag, code string, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages[tag] == nil {
+		c.messages[tag] = make(map[string]string)
+	}
+	c.messages[tag][code] = message
+}
+
+// LoadJSON reads messages in the form {"<language>": {"<code>": "<template>"}}.
+func (c *Catalog) LoadJSON(r io.Reader) error {
+	var messages map[string]map[string]string
+	if err := json.NewDecoder(r).Decode(&messages); err != nil {
+		return errorx.Wrap(err)
+	}
+	return c.load(messages)
+}
+
+// LoadYAML reads messages in the same layout as LoadJSON.
+func (c *Catalog) LoadYAML(r io.Reader) error {
+	var messages map[string]map[string]string
+	if err := yaml.NewDecoder(r).Decode(&messages); err != nil {
+		return errorx.Wrap(err)
+	}
+	return c.load(messages)
+}
+
+// LoadFile loads a .json, .yaml or .yml catalog file.
+func (c *Catalog) LoadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return errorx.Wrap(err)
+	}
+	defer f.Close()
+
+	switch ext := filepath.Ext(name); ext {
+	case ".json":
+		return c.LoadJSON(f)
+	case ".yaml", ".yml":
+		return c.LoadYAML(f)
+	default:
+		return errorx.Errorf("unsupported catalog file extension %q", ext).With("file", name)
+	}
+}
+
+func (c *Catalog) load(messages map[string]map[string]string) error {
+	for lang, codes := range messages {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return errorx.Wrap(err).With("language", lang)
+		}
+		for code, message := range codes {
+			c.Set(tag, code, message)
+		}
+	}
+	return nil
+}
+
+// Localize renders the message for err's code in tag. It tries tag and its
+// parents (e.g. "de-CH" then "de") before the catalog's fallback language and
+// finally falls back to errorx.PublicMessage.
+func (c *Catalog) Localize(err error, tag language.Tag) string {
+	if err == nil {
+		return ""
+	}
+	if message, ok := c.lookup(errorx.Code(err), tag); ok {
+		return render(message, errorx.Data(err))
+	}
+	return errorx.PublicMessage(err)
+}
+
+func (c *Catalog) lookup(code string, tag language.Tag) (string, bool) {
+	if code == "" {
+		return "", false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for {
+		if message, ok := c.messages[tag][code]; ok {
+			return message, true
+		}
+		if tag == language.Und {
+			break
+		}
+		tag = tag.Parent()
+	}
+	message, ok := c.messages[c.fallback][code]
+	return message, ok
+}
+
+// render replaces {name} parameters with values from data. Unknown
+// parameters are left untouched.
+func render(message string, data map[string]any) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(message, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(message[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(message[:start])
+		if v, ok := data[message[start+1:end]]; ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(message[start : end+1])
+		}
+		message = message[end+1:]
+	}
+	b.WriteString(message)
+	return b.String()
+}
+
+// Localize renders err with Default.
+func Localize(err error, tag language.Tag) string {
+	return Default.Localize(err, tag)
+}
@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+	"golang.org/x/text/language"
+)
+
+func TestCatalog_Localize(t *testing.T) {
+	c := New(language.English)
+	if err := c.LoadFile("testdata/catalog.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadFile("testdata/catalog.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	c.Set(language.MustParse("de-CH"), "quota_exceeded", "Kontingent von {limit} Anfragen überschritten")
+
+	notFound := errorx.WithCode(errorx.New("select from users: no rows"), "not_found").With("user_id", 42)
+	quota := errorx.WithCode(errorx.New("quota"), "quota_exceeded").With("limit", 100)
+
+	tests := []struct {
+		name string
+		err  error
+		tag  language.Tag
+		want string
+	}{
+		{name: "nil error", err: nil, tag: language.English, want: ""},
+		{name: "exact language", err: notFound, tag: language.German, want: "Benutzer 42 nicht gefunden"},
+		{name: "parent language", err: notFound, tag: language.MustParse("de-AT"), want: "Benutzer 42 nicht gefunden"},
+		{name: "regional override", err: quota, tag: language.MustParse("de-CH"), want: "Kontingent von 100 Anfragen überschritten"},
+		{name: "catalog fallback", err: notFound, tag: language.Japanese, want: "user 42 not found"},
+		{name: "yaml catalog", err: quota, tag: language.French, want: "quota de 100 requêtes dépassé"},
+		{name: "unknown code", err: errorx.WithCode(errorx.New("boom"), "unknown"), tag: language.English, want: errorx.DefaultPublicMessage},
+		{name: "no code", err: errors.New("boom"), tag: language.English, want: errorx.DefaultPublicMessage},
+		{
+			name: "sensitive parameter",
+			err:  errorx.WithSensitive(errorx.WithCode(errorx.New("no rows"), "not_found"), "user_id", 42),
+			tag:  language.English,
+			want: "user " + errorx.RedactedPlaceholder + " not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Localize(tt.err, tt.tag); got != tt.want {
+				t.Errorf("Catalog.Localize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_Load(t *testing.T) {
+	c := New(language.English)
+	tests := []struct {
+		name    string
+		load    func() error
+		wantErr bool
+	}{
+		{name: "missing file", load: func() error { return c.LoadFile("testdata/missing.json") }, wantErr: true},
+		{name: "unsupported extension", load: func() error { return c.LoadFile("testdata/catalog.txt") }, wantErr: true},
+		{name: "invalid json", load: func() error { return c.LoadJSON(strings.NewReader("{")) }, wantErr: true},
+		{name: "invalid yaml", load: func() error { return c.LoadYAML(strings.NewReader("en: [")) }, wantErr: true},
+		{name: "invalid language", load: func() error { return c.LoadJSON(strings.NewReader(`{"???": {}}`)) }, wantErr: true},
+		{name: "valid yaml", load: func() error { return c.LoadYAML(strings.NewReader("en:\n  code: message\n")) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.load(); (err != nil) != tt.wantErr {
+				t.Errorf("load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCatalog_LoadFileCause(t *testing.T) {
+	err := New(language.English).LoadFile("testdata/missing.json")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFile() error = %v, want it to wrap fs.ErrNotExist", err)
+	}
+}
+
+func Test_render(t *testing.T) {
+	data := map[string]any{"name": "alice", "n": 3}
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{message: "plain", want: "plain"},
+		{message: "hello {name}", want: "hello alice"},
+		{message: "{name} has {n} items", want: "alice has 3 items"},
+		{message: "unknown {missing}", want: "unknown {missing}"},
+		{message: "unclosed {name", want: "unclosed {name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := render(tt.message, data); got != tt.want {
+				t.Errorf("render() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	Default.Set(language.English, "test_code", "localized {id}")
+	t.Cleanup(func() { Default = New(language.English) })
+
+	if got := Localize(errorx.WithCode(errorx.New("x"), "test_code").With("id", 7), language.English); got != "localized 7" {
+		t.Errorf("Localize() = %v, want localized 7", got)
+	}
+}
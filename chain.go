@@ -0,0 +1,90 @@
+package errorx
+
+import "sync"
+
+// walk visits err and everything it wraps in pre-order, outermost first.
+// Joined errors are visited in order. It stops as soon as fn returns false.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !fn(err) {
+		return false
+	}
+	if members, ok := JoinedErrors(err); ok {
+		for _, err := range members {
+			if !walk(err, fn) {
+				return false
+			}
+		}
+		return true
+	}
+	if x, ok := err.(interface{ Unwrap() error }); ok {
+		return walk(x.Unwrap(), fn)
+	}
+	return true
+}
+
+// JoinedErrors returns the members of a joined error: the multi-errors
+// registered with RegisterMultiError and errors with an Unwrap() []error
+// method, such as those of errors.Join.
+func JoinedErrors(err error) ([]error, bool) {
+	if members, ok := multiErrors(err); ok {
+		return members, true
+	}
+	if x, ok := err.(interface{ Unwrap() []error }); ok {
+		return x.Unwrap(), true
+	}
+	return nil, false
+}
+
+var multiErrorFuncs = struct {
+	sync.RWMutex
+	funcs []func(error) ([]error, bool)
+}{}
+
+// RegisterMultiError makes Join flatten, and the functions inspecting the
+// wrap chain descend into, the multi-errors of another library. members
+// returns the members of the errors it recognizes and false for any other
+// error. The errorxmultierr package registers go.uber.org/multierr and
+// github.com/hashicorp/go-multierror.
+func RegisterMultiError(members func(error) ([]error, bool)) {
+	multiErrorFuncs.Lock()
+	defer multiErrorFuncs.Unlock()
+	multiErrorFuncs.funcs = append(multiErrorFuncs.funcs, members)
+}
+
+// multiErrors returns the members of err if a registered function
+// recognizes it.
+func multiErrors(err error) ([]error, bool) {
+	multiErrorFuncs.RLock()
+	defer multiErrorFuncs.RUnlock()
+	for _, members := range multiErrorFuncs.funcs {
+		if errs, ok := members(err); ok {
+			return errs, true
+		}
+	}
+	return nil, false
+}
+
+// walkCustom is walk restricted to *customError layers.
+func walkCustom(err error, fn func(*customError) bool) {
+	walk(err, func(err error) bool {
+		if e, ok := err.(*customError); ok && e != nil {
+			return fn(e)
+		}
+		return true
+	})
+}
+
+// deepestStack returns the innermost non-empty stack in err's wrap chain.
+func deepestStack(err error) stack {
+	var st stack
+	walkCustom(err, func(e *customError) bool {
+		if len(e.stack) > 0 {
+			st = e.stack
+		}
+		return true
+	})
+	return st
+}
@@ -0,0 +1,61 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_walk(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := New("err2")
+	wrapped := fmt.Errorf("wrapped: %w", err2)
+	joined := Join(err1, wrapped)
+
+	tests := []struct {
+		name string
+		err  error
+		stop error
+		want []error
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "wrap chain",
+			err:  wrapped,
+			want: []error{wrapped, err2, err2.Unwrap()},
+		},
+		{
+			name: "joined errors",
+			err:  joined,
+			want: []error{joined, joined[0], err1, joined[1], wrapped, err2, err2.Unwrap()},
+		},
+		{
+			name: "stop early",
+			err:  joined,
+			stop: err1,
+			want: []error{joined, joined[0], err1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []error
+			walk(tt.err, func(err error) bool {
+				got = append(got, err)
+				return err != tt.stop
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("walk() visited %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("walk() visited[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+func runDiff(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	split := fs.String("split", "", "point in time to compare around, e.g. a deploy")
+	window := fs.Duration("window", 24*time.Hour, "length of the windows before and after -split")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *split == "" {
+		return fmt.Errorf("diff requires -split")
+	}
+	at, err := parseTime(*split)
+	if err != nil {
+		return err
+	}
+	records, err := readJournals(fs.Args(), stderr)
+	if err != nil {
+		return err
+	}
+
+	before := summarize(filterRecords(records, timeRange{since: at.Add(-*window), until: at}))
+	after := summarize(filterRecords(records, timeRange{since: at, until: at.Add(*window)}))
+
+	var rows []diffRow
+	for fp, s := range after {
+		var prev int
+		if b, ok := before[fp]; ok {
+			prev = b.count
+		}
+		rows = append(rows, diffRow{summary: s, before: prev})
+	}
+	for fp, s := range before {
+		if _, ok := after[fp]; !ok {
+			rows = append(rows, diffRow{summary: s, before: s.count, gone: true})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if a, b := statusOrder[rows[i].status()], statusOrder[rows[j].status()]; a != b {
+			return a < b
+		}
+		return rows[i].fingerprint < rows[j].fingerprint
+	})
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tBEFORE\tAFTER\tFINGERPRINT\tCODE\tMESSAGE")
+	for _, row := range rows {
+		after := row.count
+		if row.gone {
+			after = 0
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", row.status(), row.before, after, row.fingerprint, row.code, row.message)
+	}
+	return tw.Flush()
+}
+
+type diffRow struct {
+	*summary
+	before int
+	gone   bool
+}
+
+var statusOrder = map[string]int{"new": 0, "increased": 1, "reduced": 2, "same": 3, "resolved": 4}
+
+func (r diffRow) status() string {
+	switch {
+	case r.gone:
+		return "resolved"
+	case r.before == 0:
+		return "new"
+	case r.count > r.before:
+		return "increased"
+	case r.count < r.before:
+		return "reduced"
+	default:
+		return "same"
+	}
+}
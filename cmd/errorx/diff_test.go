@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func TestDiff(t *testing.T) {
+	path := writeJournal(t, map[time.Duration]error{
+		-3 * time.Hour:    notFoundError(1),
+		-2 * time.Hour:    errorx.WithCode(errorx.New("cache miss"), "cache"),
+		-1 * time.Hour:    notFoundError(2),
+		-30 * time.Minute: notFoundError(3),
+		-10 * time.Minute: timeoutError(),
+	})
+
+	got, err := runCommand(t, "diff", "-split", "90m", "-window", "2h", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("diff output = %q, want header and 3 rows", got)
+	}
+	for i, want := range []string{"new  ", "increased", "resolved"} {
+		if !strings.HasPrefix(lines[i+1], want) {
+			t.Errorf("diff row %d = %q, want prefix %q", i, lines[i+1], want)
+		}
+	}
+	if !strings.Contains(lines[1], "upstream timeout") || !strings.Contains(lines[3], "cache miss") {
+		t.Errorf("diff output = %q", got)
+	}
+
+	if _, err := runCommand(t, "diff", path); err == nil {
+		t.Error("diff without -split error = nil, want error")
+	}
+}
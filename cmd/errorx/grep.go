@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func runGrep(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("grep", flag.ContinueOnError)
+	var rf rangeFlags
+	rf.register(fs)
+	key := fs.String("key", "", "data key to match")
+	value := fs.String("value", "", "regular expression the value must match")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *key == "" {
+		return fmt.Errorf("grep requires -key")
+	}
+	re, err := regexp.Compile(*value)
+	if err != nil {
+		return err
+	}
+	r, err := rf.timeRange()
+	if err != nil {
+		return err
+	}
+	records, err := readJournals(fs.Args(), stderr)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tFINGERPRINT\tVALUE\tMESSAGE")
+	for _, rec := range filterRecords(records, r) {
+		v, ok := errorx.Data(rec.Err)[*key]
+		if !ok {
+			continue
+		}
+		s := fmt.Sprint(v)
+		if !re.MatchString(s) {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", rec.Time.Format(time.RFC3339), rec.Fingerprint, s, firstLine(rec.Err.Error()))
+	}
+	return tw.Flush()
+}
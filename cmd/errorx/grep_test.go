@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGrep(t *testing.T) {
+	path := writeJournal(t, map[time.Duration]error{
+		-3 * time.Hour: notFoundError(17),
+		-2 * time.Hour: notFoundError(42),
+		-1 * time.Hour: timeoutError(),
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantRows []string
+	}{
+		{name: "key only", args: []string{"-key", "user_id"}, wantRows: []string{"17", "42"}},
+		{name: "value pattern", args: []string{"-key", "user_id", "-value", "^4"}, wantRows: []string{"42"}},
+		{name: "other key", args: []string{"-key", "upstream"}, wantRows: []string{"billing"}},
+		{name: "missing key", args: []string{"-key", "nope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runCommand(t, append(append([]string{"grep"}, tt.args...), path)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(got), "\n")[1:]
+			if len(lines) != len(tt.wantRows) {
+				t.Fatalf("grep output = %q, want %d rows", got, len(tt.wantRows))
+			}
+			for i, want := range tt.wantRows {
+				if !strings.Contains(lines[i], "  "+want+"  ") {
+					t.Errorf("grep row %d = %q, want value %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+
+	if _, err := runCommand(t, "grep", path); err == nil {
+		t.Error("grep without -key error = nil, want error")
+	}
+	if _, err := runCommand(t, "grep", "-key", "x", "-value", "(", path); err == nil {
+		t.Error("grep with invalid -value error = nil, want error")
+	}
+}
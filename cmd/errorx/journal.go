@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/ice-coldbell/errorx/report"
+)
+
+// for test injection
+var timeNow = time.Now
+
+// parseTime accepts an RFC 3339 timestamp or a duration before now.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return timeNow().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: want RFC 3339 or a duration", s)
+	}
+	return t, nil
+}
+
+// timeRange is a half-open [since, until) interval; zero bounds are open.
+type timeRange struct {
+	since, until time.Time
+}
+
+func (r timeRange) contains(t time.Time) bool {
+	return (r.since.IsZero() || !t.Before(r.since)) && (r.until.IsZero() || t.Before(r.until))
+}
+
+type rangeFlags struct {
+	since, until string
+}
+
+func (f *rangeFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.since, "since", "", "only records at or after this time")
+	fs.StringVar(&f.until, "until", "", "only records before this time")
+}
+
+func (f *rangeFlags) timeRange() (timeRange, error) {
+	since, err := parseTime(f.since)
+	if err != nil {
+		return timeRange{}, err
+	}
+	until, err := parseTime(f.until)
+	if err != nil {
+		return timeRange{}, err
+	}
+	return timeRange{since: since, until: until}, nil
+}
+
+// readJournals reads the records of every named journal. Malformed lines
+// and records without an error are skipped with a warning on stderr.
+func readJournals(names []string, stderr io.Writer) ([]report.Record, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no journal files given")
+	}
+	var records []report.Record
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		rs, skipped, err := report.ReadJournal(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		for _, rec := range rs {
+			if rec.Err == nil {
+				skipped++
+				continue
+			}
+			records = append(records, rec)
+		}
+		if skipped > 0 {
+			fmt.Fprintf(stderr, "errorx: %s: skipped %d malformed lines\n", name, skipped)
+		}
+	}
+	return records, nil
+}
+
+func filterRecords(records []report.Record, r timeRange) []report.Record {
+	var out []report.Record
+	for _, rec := range records {
+		if r.contains(rec.Time) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// summary aggregates the records sharing a fingerprint.
+type summary struct {
+	fingerprint string
+	code        string
+	message     string
+	count       int
+	first, last time.Time
+}
+
+func summarize(records []report.Record) map[string]*summary {
+	out := make(map[string]*summary)
+	for _, rec := range records {
+		s, ok := out[rec.Fingerprint]
+		if !ok {
+			s = &summary{fingerprint: rec.Fingerprint, first: rec.Time}
+			out[rec.Fingerprint] = s
+		}
+		s.count++
+		if rec.Time.Before(s.first) {
+			s.first = rec.Time
+		}
+		if !rec.Time.Before(s.last) {
+			s.last = rec.Time
+			s.code = errorx.Code(rec.Err)
+			s.message = firstLine(rec.Err.Error())
+		}
+	}
+	return out
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " ..."
+	}
+	return s
+}
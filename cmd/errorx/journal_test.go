@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseTime(t *testing.T) {
+	timeNow = func() time.Time { return testNow }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "", want: time.Time{}},
+		{in: "2h", want: testNow.Add(-2 * time.Hour)},
+		{in: "2024-02-01T00:00:00Z", want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseTime(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_timeRange_contains(t *testing.T) {
+	r := timeRange{since: testNow, until: testNow.Add(time.Hour)}
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{t: testNow.Add(-time.Second), want: false},
+		{t: testNow, want: true},
+		{t: testNow.Add(time.Hour - time.Second), want: true},
+		{t: testNow.Add(time.Hour), want: false},
+	}
+	for _, tt := range tests {
+		if got := r.contains(tt.t); got != tt.want {
+			t.Errorf("timeRange.contains(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+	if !(timeRange{}).contains(testNow) {
+		t.Error("empty timeRange.contains() = false, want true")
+	}
+}
@@ -0,0 +1,79 @@
+// Command errorx inspects JSON Lines error journals written by the report
+// package and migrates code to errorx.
+//
+// Usage:
+//
+//	errorx top [-since T] [-until T] [-n N] journal...
+//	errorx show [-since T] [-until T] fingerprint journal...
+//	errorx diff -split T [-window D] journal...
+//	errorx grep -key K [-value REGEXP] journal...
+//	errorx symbolize -binary BINARY [rawstack.json...]
+//	errorx migrate [-d] path...
+//
+// Times are RFC 3339 timestamps or durations counted back from now, such as
+// "24h". Gzipped journals are read transparently. symbolize reads the JSON
+// encoding of rawstack.Stack values from the given files or stdin. migrate
+// rewrites github.com/pkg/errors and fmt.Errorf calls in the Go files below
+// the given paths to errorx, or prints the diffs with -d, and lists the call
+// sites it leaves for manual migration.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type command struct {
+	usage string
+	run   func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = map[string]command{
+	"top":  {usage: "most frequent fingerprints", run: runTop},
+	"show": {usage: "print the latest occurrence of a fingerprint", run: runShow},
+	"diff": {usage: "compare fingerprints before and after a point in time", run: runDiff},
+	"grep": {usage: "find records by data key and value", run: runGrep},
+	"symbolize": {
+		usage: "resolve raw stacks against the binary they came from",
+		run:   runSymbolize,
+	},
+	"migrate": {usage: "rewrite pkg/errors and fmt.Errorf calls to errorx", run: runMigrate},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "errorx:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) error {
+	if len(args) == 0 {
+		usage(stderr)
+		return fmt.Errorf("missing command")
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		usage(stderr)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	return cmd.run(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("usage: errorx <command> [flags] [args]\n\ncommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-8s %s\n", name, commands[name].usage)
+	}
+	io.WriteString(w, b.String())
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/ice-coldbell/errorx/report"
+)
+
+var testNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func notFoundError(id int) error {
+	return errorx.WithCode(errorx.New("user not found"), "not_found").With("user_id", id)
+}
+
+func timeoutError() error {
+	return errorx.WithCode(errorx.New("upstream timeout"), "timeout").With("upstream", "billing")
+}
+
+// writeJournal writes events at the given offsets from testNow.
+func writeJournal(t *testing.T, events map[time.Duration]error) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, err := report.NewJournal(path, report.JournalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	offsets := make([]time.Duration, 0, len(events))
+	for offset := range events {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	for _, offset := range offsets {
+		ev := report.NewEvent(events[offset])
+		ev.Time = testNow.Add(offset)
+		if err := j.Send(context.Background(), []report.Event{ev}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func runCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	timeNow = func() time.Time { return testNow }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	var stdout, stderr bytes.Buffer
+	err := run(args, &stdout, &stderr)
+	return stdout.String() + stderr.String(), err
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "no command", args: nil, want: "usage: errorx", wantErr: true},
+		{name: "unknown command", args: []string{"nope"}, want: "usage: errorx", wantErr: true},
+		{name: "missing journal", args: []string{"top"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runCommand(t, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("run() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/imports"
+)
+
+const (
+	errorxPath    = "github.com/ice-coldbell/errorx"
+	pkgErrorsPath = "github.com/pkg/errors"
+)
+
+// migration is the errorx replacement of a github.com/pkg/errors function.
+type migration struct {
+	name string
+	// sprintf folds the format arguments after the error into fmt.Sprintf.
+	sprintf bool
+	// custom marks replacements returning errorx.CustomError instead of
+	// error.
+	custom bool
+}
+
+var pkgErrorsMigrations = map[string]migration{
+	"New":          {name: "New", custom: true},
+	"Errorf":       {name: "Errorf", custom: true},
+	"Wrap":         {name: "WithMessage", custom: true},
+	"Wrapf":        {name: "WithMessage", sprintf: true, custom: true},
+	"WithMessage":  {name: "WithMessage", custom: true},
+	"WithMessagef": {name: "WithMessage", sprintf: true, custom: true},
+	"WithStack":    {name: "Wrap", custom: true},
+	"Is":           {name: "Is"},
+	"As":           {name: "As"},
+	"Unwrap":       {name: "Unwrap"},
+	"Cause":        {name: "Cause"},
+}
+
+func runMigrate(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	diff := fs.Bool("d", false, "print diffs instead of rewriting files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("migrate requires a file or directory")
+	}
+	files, err := goFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var reports int
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		out, notes, err := migrateFile(path, src)
+		if err != nil {
+			return err
+		}
+		for _, note := range notes {
+			fmt.Fprintln(stdout, note)
+		}
+		reports += len(notes)
+		if bytes.Equal(src, out) {
+			continue
+		}
+		if *diff {
+			io.WriteString(stdout, unifiedDiff(path, string(src), string(out)))
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, out, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if reports > 0 {
+		return fmt.Errorf("%d call sites need manual migration", reports)
+	}
+	return nil
+}
+
+// goFiles expands directories in paths to the .go files below them,
+// skipping the directories the go tool ignores.
+func goFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := d.Name()
+			if d.IsDir() {
+				if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if path == root || strings.HasSuffix(name, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+type migrator struct {
+	fset    *token.FileSet
+	errors  string // local name of github.com/pkg/errors, "" if not imported
+	fmt     string // local name of fmt, "" if not imported
+	changed bool
+	// sprintf is set when a rewrite added a fmt.Sprintf call.
+	sprintf bool
+	// done holds the selectors of calls already handled.
+	done  map[*ast.SelectorExpr]bool
+	notes []string
+}
+
+// migrateFile rewrites the pkg/errors and fmt.Errorf calls of the Go source
+// src. It returns the new source and a note for every call site left
+// unchanged because it cannot be converted safely.
+func migrateFile(path string, src []byte) ([]byte, []string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ast.IsGenerated(f) {
+		return src, nil, nil
+	}
+
+	m := &migrator{fset: fset, done: make(map[*ast.SelectorExpr]bool)}
+	errorsSpec, fmtSpec := importSpec(f, pkgErrorsPath), importSpec(f, "fmt")
+	if errorsSpec != nil {
+		m.errors = importName(errorsSpec)
+	}
+	if fmtSpec != nil {
+		m.fmt = importName(fmtSpec)
+	}
+	if m.errors == "" && m.fmt == "" {
+		return src, nil, nil
+	}
+
+	astutil.Apply(f, func(c *astutil.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.CallExpr:
+			m.call(c, n)
+		case *ast.SelectorExpr:
+			if !m.done[n] && isPackage(n.X, m.errors) {
+				if _, ok := pkgErrorsMigrations[n.Sel.Name]; ok {
+					m.note(n, "cannot convert %s.%s used as a value", m.errors, n.Sel.Name)
+				} else {
+					m.note(n, "cannot convert %s.%s: errorx has no equivalent", m.errors, n.Sel.Name)
+				}
+			}
+		}
+		return true
+	}, nil)
+	if !m.changed {
+		return src, m.notes, nil
+	}
+
+	if errorsSpec != nil && !uses(f, m.errors) {
+		astutil.DeleteNamedImport(fset, f, specName(errorsSpec), pkgErrorsPath)
+	}
+	if fmtSpec != nil && !uses(f, m.fmt) {
+		astutil.DeleteNamedImport(fset, f, specName(fmtSpec), "fmt")
+	}
+	if m.sprintf && fmtSpec == nil {
+		astutil.AddImport(fset, f, "fmt")
+	}
+	astutil.AddImport(fset, f, errorxPath)
+
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, fset, f); err != nil {
+		return nil, nil, err
+	}
+	// Process formats the result and groups the standard library imports
+	// apart from errorx.
+	out, err := imports.Process(path, buf.Bytes(), &imports.Options{FormatOnly: true, Comments: true, TabIndent: true, TabWidth: 8})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, m.notes, nil
+}
+
+func (m *migrator) call(c *astutil.Cursor, call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	var mig migration
+	switch {
+	case isPackage(sel.X, m.errors):
+		if mig, ok = pkgErrorsMigrations[sel.Sel.Name]; !ok {
+			return
+		}
+	case isPackage(sel.X, m.fmt) && sel.Sel.Name == "Errorf":
+		mig = migration{name: "Errorf", custom: true}
+	default:
+		return
+	}
+	m.done[sel] = true
+	name := sel.X.(*ast.Ident).Name + "." + sel.Sel.Name
+
+	if mig.custom && definesVariable(c.Parent()) {
+		m.note(call, "cannot convert %s: the variable it initializes would become errorx.CustomError", name)
+		return
+	}
+	if mig.sprintf {
+		if len(call.Args) < 2 {
+			m.note(call, "cannot convert %s without a format", name)
+			return
+		}
+		fmtName := m.fmt
+		if fmtName == "" {
+			fmtName = "fmt"
+		}
+		sprintf := &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   &ast.Ident{Name: fmtName, NamePos: call.Args[1].Pos()},
+				Sel: ast.NewIdent("Sprintf"),
+			},
+			Args:     call.Args[1:],
+			Ellipsis: call.Ellipsis,
+		}
+		call.Args = []ast.Expr{call.Args[0], sprintf}
+		call.Ellipsis = token.NoPos
+		m.sprintf = true
+	}
+	sel.X.(*ast.Ident).Name = "errorx"
+	sel.Sel.Name = mig.name
+	m.changed = true
+}
+
+func (m *migrator) note(n ast.Node, format string, args ...any) {
+	m.notes = append(m.notes, fmt.Sprintf("%s: %s", m.fset.Position(n.Pos()), fmt.Sprintf(format, args...)))
+}
+
+// definesVariable reports whether parent declares variables typed by their
+// initial values, whose type would change with the rewrite.
+func definesVariable(parent ast.Node) bool {
+	switch p := parent.(type) {
+	case *ast.AssignStmt:
+		return p.Tok == token.DEFINE
+	case *ast.ValueSpec:
+		return p.Type == nil
+	}
+	return false
+}
+
+// isPackage reports whether x is the package name, as opposed to a local
+// variable shadowing it.
+func isPackage(x ast.Expr, name string) bool {
+	id, ok := x.(*ast.Ident)
+	return ok && name != "" && id.Name == name && id.Obj == nil
+}
+
+func importSpec(f *ast.File, path string) *ast.ImportSpec {
+	for _, spec := range f.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == path {
+			return spec
+		}
+	}
+	return nil
+}
+
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	p, _ := strconv.Unquote(spec.Path.Value)
+	return p[strings.LastIndex(p, "/")+1:]
+}
+
+func specName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	return ""
+}
+
+func uses(f *ast.File, name string) bool {
+	var used bool
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && isPackage(sel.X, name) {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
+
+// unifiedDiff returns the changes from a to b in unified format with three
+// lines of context. Relative paths get the a/ and b/ prefixes of git diffs;
+// absolute paths are used as they are.
+func unifiedDiff(path, a, b string) string {
+	const context = 3
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	// pos[i] holds the line numbers in a and b before ops[i].
+	pos := make([][2]int, len(ops)+1)
+	for i, op := range ops {
+		pos[i+1] = pos[i]
+		if op.kind != '+' {
+			pos[i+1][0]++
+		}
+		if op.kind != '-' {
+			pos[i+1][1]++
+		}
+	}
+
+	var buf strings.Builder
+	from, to := "a/"+filepath.ToSlash(path), "b/"+filepath.ToSlash(path)
+	if filepath.IsAbs(path) {
+		from, to = filepath.ToSlash(path), filepath.ToSlash(path)
+	}
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", from, to)
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		start, end := max(i-context, 0), i
+		for {
+			for end < len(ops) && ops[end].kind != ' ' {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next < len(ops) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			end = min(end+context, len(ops))
+			break
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(pos[start][0], pos[end][0]-pos[start][0]),
+			hunkRange(pos[start][1], pos[end][1]-pos[start][1]))
+		for _, op := range ops[start:end] {
+			fmt.Fprintf(&buf, "%c%s\n", op.kind, op.text)
+		}
+		i = end
+	}
+	return buf.String()
+}
+
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// diffLines returns a shortest edit script from a to b using Myers'
+// algorithm.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[y-1]})
+			y--
+		} else {
+			ops = append(ops, diffOp{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{' ', a[x-1]})
+		x--
+		y--
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMigrateFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		want      string
+		wantNotes []string
+	}{
+		{
+			name: "pkg errors",
+			src: `package p
+
+import "github.com/pkg/errors"
+
+var errNotFound error = errors.New("not found")
+
+func load(id int) error {
+	if err := read(id); err != nil {
+		return errors.Wrap(err, "load")
+	}
+	if errors.Is(errNotFound, nil) {
+		return errors.WithStack(errNotFound)
+	}
+	return errors.Errorf("load %d", id)
+}
+`,
+			want: `package p
+
+import "github.com/ice-coldbell/errorx"
+
+var errNotFound error = errorx.New("not found")
+
+func load(id int) error {
+	if err := read(id); err != nil {
+		return errorx.WithMessage(err, "load")
+	}
+	if errorx.Is(errNotFound, nil) {
+		return errorx.Wrap(errNotFound)
+	}
+	return errorx.Errorf("load %d", id)
+}
+`,
+		},
+		{
+			name: "wrapf adds fmt",
+			src: `package p
+
+import "github.com/pkg/errors"
+
+func load(id int, err error) error {
+	return errors.Wrapf(err, "load %d", id)
+}
+`,
+			want: `package p
+
+import (
+	"fmt"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func load(id int, err error) error {
+	return errorx.WithMessage(err, fmt.Sprintf("load %d", id))
+}
+`,
+		},
+		{
+			name: "fmt errorf",
+			src: `package p
+
+import "fmt"
+
+func load(id int, err error) error {
+	return fmt.Errorf("load %d: %w", id, err)
+}
+`,
+			want: `package p
+
+import "github.com/ice-coldbell/errorx"
+
+func load(id int, err error) error {
+	return errorx.Errorf("load %d: %w", id, err)
+}
+`,
+		},
+		{
+			name: "fmt still used",
+			src: `package p
+
+import "fmt"
+
+func load(id int) error {
+	fmt.Println(id)
+	return fmt.Errorf("load %d", id)
+}
+`,
+			want: `package p
+
+import (
+	"fmt"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func load(id int) error {
+	fmt.Println(id)
+	return errorx.Errorf("load %d", id)
+}
+`,
+		},
+		{
+			name: "unsafe sites",
+			src: `package p
+
+import (
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+var ErrNotFound = pkgerrors.New("not found")
+
+func load(err error) error {
+	wrapped := fmt.Errorf("load: %w", err)
+	if pkgerrors.Cause(wrapped) == ErrNotFound {
+		return pkgerrors.WithMessage(wrapped, "missing")
+	}
+	wrap := pkgerrors.Wrap
+	return wrap(wrapped, "load")
+}
+`,
+			want: `package p
+
+import (
+	"fmt"
+
+	"github.com/ice-coldbell/errorx"
+	pkgerrors "github.com/pkg/errors"
+)
+
+var ErrNotFound = pkgerrors.New("not found")
+
+func load(err error) error {
+	wrapped := fmt.Errorf("load: %w", err)
+	if errorx.Cause(wrapped) == ErrNotFound {
+		return errorx.WithMessage(wrapped, "missing")
+	}
+	wrap := pkgerrors.Wrap
+	return wrap(wrapped, "load")
+}
+`,
+			wantNotes: []string{
+				"p.go:9:19: cannot convert pkgerrors.New: the variable it initializes would become errorx.CustomError",
+				"p.go:12:13: cannot convert fmt.Errorf: the variable it initializes would become errorx.CustomError",
+				"p.go:16:10: cannot convert pkgerrors.Wrap used as a value",
+			},
+		},
+		{
+			name: "shadowed package",
+			src: `package p
+
+import "fmt"
+
+type printer struct{}
+
+func (printer) Errorf(string, ...any) error { return nil }
+
+func load() error {
+	fmt := printer{}
+	return fmt.Errorf("load")
+}
+`,
+			want: `package p
+
+import "fmt"
+
+type printer struct{}
+
+func (printer) Errorf(string, ...any) error { return nil }
+
+func load() error {
+	fmt := printer{}
+	return fmt.Errorf("load")
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notes, err := migrateFile("p.go", []byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("migrateFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(notes, tt.wantNotes) {
+				t.Errorf("migrateFile() notes = %q, want %q", notes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nimport \"fmt\"\n\nfunc f() error {\n\treturn fmt.Errorf(\"x\")\n}\n"
+	path := filepath.Join(dir, "p.go")
+	skipped := filepath.Join(dir, "testdata", "q.go")
+	for _, name := range []string{path, skipped} {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := runCommand(t, "migrate", "-d", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDiff := "@@ -1,7 +1,7 @@\n package p\n \n-import \"fmt\"\n+import \"github.com/ice-coldbell/errorx\"\n \n func f() error {\n-\treturn fmt.Errorf(\"x\")\n+\treturn errorx.Errorf(\"x\")\n }\n"
+	if !strings.HasSuffix(got, wantDiff) {
+		t.Errorf("migrate -d output =\n%s\nwant suffix\n%s", got, wantDiff)
+	}
+	if b, _ := os.ReadFile(path); string(b) != src {
+		t.Errorf("migrate -d rewrote %s", path)
+	}
+
+	if _, err := runCommand(t, "migrate", dir); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); !strings.Contains(string(b), "errorx.Errorf") {
+		t.Errorf("migrate did not rewrite %s:\n%s", path, b)
+	}
+	if b, _ := os.ReadFile(skipped); string(b) != src {
+		t.Errorf("migrate rewrote %s", skipped)
+	}
+
+	unsafe := filepath.Join(dir, "u.go")
+	if err := os.WriteFile(unsafe, []byte("package p\n\nimport \"fmt\"\n\nvar e = fmt.Errorf(\"x\")\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = runCommand(t, "migrate", unsafe)
+	if err == nil || !strings.Contains(got, "u.go:5:9: cannot convert fmt.Errorf") {
+		t.Errorf("migrate unsafe = %q, %v; want a note and an error", got, err)
+	}
+
+	if _, err := runCommand(t, "migrate"); err == nil {
+		t.Error("migrate without paths error = nil, want error")
+	}
+}
+
+func Test_unifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := `--- a/f
++++ b/f
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if got := unifiedDiff("f", a, b); got != want {
+		t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+	if got := unifiedDiff("f", a, a); got != "--- a/f\n+++ b/f\n" {
+		t.Errorf("unifiedDiff() of equal inputs = %q", got)
+	}
+	if got := unifiedDiff("f", "", "x\n"); !strings.HasSuffix(got, "@@ -0,0 +1,1 @@\n+x\n") {
+		t.Errorf("unifiedDiff() from empty = %q", got)
+	}
+	abs := filepath.Join(t.TempDir(), "f.go")
+	if got, want := unifiedDiff(abs, a, a), fmt.Sprintf("--- %[1]s\n+++ %[1]s\n", filepath.ToSlash(abs)); got != want {
+		t.Errorf("unifiedDiff() of absolute path = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ice-coldbell/errorx/report"
+)
+
+func runShow(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	var rf rangeFlags
+	rf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: errorx show [flags] fingerprint journal...")
+	}
+	r, err := rf.timeRange()
+	if err != nil {
+		return err
+	}
+	fingerprint := fs.Arg(0)
+	records, err := readJournals(fs.Args()[1:], stderr)
+	if err != nil {
+		return err
+	}
+
+	var (
+		latest *report.Record
+		count  int
+	)
+	records = filterRecords(records, r)
+	for i := range records {
+		if records[i].Fingerprint != fingerprint {
+			continue
+		}
+		count++
+		if latest == nil || !records[i].Time.Before(latest.Time) {
+			latest = &records[i]
+		}
+	}
+	if latest == nil {
+		return fmt.Errorf("fingerprint %s not found", fingerprint)
+	}
+
+	fmt.Fprintf(stdout, "fingerprint: %s\noccurrences: %d\nlast seen:   %s\n", fingerprint, count, latest.Time.Format(time.RFC3339))
+	if latest.Hostname != "" {
+		fmt.Fprintf(stdout, "hostname:    %s\n", latest.Hostname)
+	}
+	fmt.Fprintf(stdout, "\n%+v\n", latest.Err)
+	return nil
+}
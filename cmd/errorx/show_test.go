@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ice-coldbell/errorx/report"
+)
+
+func TestShow(t *testing.T) {
+	path := writeJournal(t, map[time.Duration]error{
+		-2 * time.Hour: notFoundError(1),
+		-1 * time.Hour: notFoundError(2),
+	})
+	fp := report.NewEvent(notFoundError(0)).Fingerprint
+
+	got, err := runCommand(t, "show", fp, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"fingerprint: " + fp,
+		"occurrences: 2",
+		"last seen:   " + testNow.Add(-time.Hour).Format(time.RFC3339),
+		"user not found\ncode: not_found\ndata: user_id=2",
+		"notFoundError",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("show output = %q, want %q", got, want)
+		}
+	}
+
+	if _, err := runCommand(t, "show", "unknown", path); err == nil {
+		t.Error("show unknown error = nil, want error")
+	}
+	if _, err := runCommand(t, "show"); err == nil {
+		t.Error("show without fingerprint error = nil, want error")
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ice-coldbell/errorx/rawstack"
+	"github.com/ice-coldbell/errorx/symbolize"
+)
+
+// for test injection
+var stdin io.Reader = os.Stdin
+
+func runSymbolize(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("symbolize", flag.ContinueOnError)
+	binary := fs.String("binary", "", "ELF binary the stacks were captured from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *binary == "" {
+		return fmt.Errorf("symbolize requires -binary")
+	}
+	b, err := symbolize.Open(*binary)
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	inputs := []io.Reader{stdin}
+	if fs.NArg() > 0 {
+		inputs = inputs[:0]
+		for _, name := range fs.Args() {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			inputs = append(inputs, f)
+		}
+	}
+
+	n := 0
+	for _, in := range inputs {
+		dec := json.NewDecoder(in)
+		for {
+			var raw rawstack.Stack
+			if err := dec.Decode(&raw); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			frames, err := b.Symbolize(raw)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				fmt.Fprintln(stdout)
+			}
+			n++
+			printFrames(stdout, frames)
+		}
+	}
+	return nil
+}
+
+func printFrames(w io.Writer, frames []symbolize.Frame) {
+	for _, f := range frames {
+		if f.Function == "" {
+			fmt.Fprintf(w, "%#x\n\t?\n", f.PC)
+			continue
+		}
+		inlined := ""
+		if f.Inlined {
+			inlined = " (inlined)"
+		}
+		fmt.Fprintf(w, "%s%s\n\t%s:%d\n", f.Function, inlined, f.File, f.Line)
+	}
+}
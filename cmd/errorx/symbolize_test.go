@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/ice-coldbell/errorx/rawstack"
+)
+
+func TestSymbolize(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("test binary is not ELF")
+	}
+	bin, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(rawstack.Of(errorx.New("test")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin = strings.NewReader(string(raw) + "\n" + string(raw))
+	t.Cleanup(func() { stdin = os.Stdin })
+	got, err := runCommand(t, "symbolize", "-binary", bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(got, "cmd/errorx.TestSymbolize\n\t"); n != 2 {
+		t.Errorf("symbolize output = %q, want 2 stacks with TestSymbolize", got)
+	}
+
+	file := filepath.Join(t.TempDir(), "raw.json")
+	if err := os.WriteFile(file, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = runCommand(t, "symbolize", "-binary", bin, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "symbolize_test.go:") {
+		t.Errorf("symbolize output = %q, want file and line", got)
+	}
+
+	if _, err := runCommand(t, "symbolize"); err == nil {
+		t.Error("symbolize without -binary error = nil, want error")
+	}
+	stdin = strings.NewReader(`{"build_id": "other"}`)
+	if _, err := runCommand(t, "symbolize", "-binary", bin); err == nil {
+		t.Error("symbolize with wrong build ID error = nil, want error")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+func runTop(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("top", flag.ContinueOnError)
+	var rf rangeFlags
+	rf.register(fs)
+	n := fs.Int("n", 10, "number of fingerprints to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	r, err := rf.timeRange()
+	if err != nil {
+		return err
+	}
+	records, err := readJournals(fs.Args(), stderr)
+	if err != nil {
+		return err
+	}
+
+	summaries := sortedSummaries(summarize(filterRecords(records, r)))
+	if *n > 0 && len(summaries) > *n {
+		summaries = summaries[:*n]
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COUNT\tFINGERPRINT\tCODE\tLAST SEEN\tMESSAGE")
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", s.count, s.fingerprint, s.code, s.last.Format(time.RFC3339), s.message)
+	}
+	return tw.Flush()
+}
+
+// sortedSummaries orders by count, most frequent first.
+func sortedSummaries(m map[string]*summary) []*summary {
+	out := make([]*summary, 0, len(m))
+	for _, s := range m {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].count != out[j].count {
+			return out[i].count > out[j].count
+		}
+		return out[i].fingerprint < out[j].fingerprint
+	})
+	return out
+}
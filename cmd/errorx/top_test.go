@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ice-coldbell/errorx/report"
+)
+
+func TestTop(t *testing.T) {
+	path := writeJournal(t, map[time.Duration]error{
+		-3 * time.Hour:    notFoundError(1),
+		-2 * time.Hour:    notFoundError(2),
+		-1 * time.Hour:    notFoundError(3),
+		-30 * time.Minute: timeoutError(),
+		-5 * time.Hour:    errors.New("old"),
+	})
+
+	got, err := runCommand(t, "top", "-since", "4h", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("top output = %q, want header and 2 rows", got)
+	}
+	fp := report.NewEvent(notFoundError(0)).Fingerprint
+	if !strings.HasPrefix(lines[1], "3 ") || !strings.Contains(lines[1], fp) || !strings.Contains(lines[1], "not_found") {
+		t.Errorf("top first row = %q, want 3 not_found occurrences of %s", lines[1], fp)
+	}
+	if !strings.HasPrefix(lines[2], "1 ") || !strings.Contains(lines[2], "upstream timeout") {
+		t.Errorf("top second row = %q, want 1 timeout", lines[2])
+	}
+
+	got, err = runCommand(t, "top", "-n", "1", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(strings.TrimSpace(got), "\n"); n != 1 {
+		t.Errorf("top -n 1 output = %q, want a single row", got)
+	}
+
+	if _, err := runCommand(t, "top", "-since", "bogus", path); err == nil {
+		t.Error("top -since bogus error = nil, want error")
+	}
+}
+
+func TestTop_skipsRecordsWithoutError(t *testing.T) {
+	path := writeJournal(t, map[time.Duration]error{-time.Hour: timeoutError()})
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("{\"fingerprint\":\"abc\",\"timestamp\":\"2026-10-18T00:00:00Z\"}\n{\"torn\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for _, args := range [][]string{{"top", path}, {"diff", "-split", "90m", path}} {
+		got, err := runCommand(t, args...)
+		if err != nil {
+			t.Fatalf("%s error = %v", args[0], err)
+		}
+		if !strings.Contains(got, "upstream timeout") || !strings.Contains(got, "skipped 2 malformed lines") {
+			t.Errorf("%s output = %q, want the timeout and a warning for 2 skipped lines", args[0], got)
+		}
+	}
+}
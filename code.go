@@ -0,0 +1,16 @@
+package errorx
+
+// WithCode sets a machine-readable code, such as "not_found", on err.
+func WithCode(err error, code string) CustomError {
+	return custom(err).withCode(code)
+}
+
+// Code returns the outermost code set with WithCode in the wrap chain.
+func Code(err error) string {
+	var code string
+	walkCustom(err, func(e *customError) bool {
+		code = e.code
+		return code == ""
+	})
+	return code
+}
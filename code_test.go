@@ -0,0 +1,50 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	inner := WithCode(New("inner"), "inner_code")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil error", err: nil, want: ""},
+		{name: "std error", err: errors.New("std"), want: ""},
+		{name: "single layer", err: inner, want: "inner_code"},
+		{name: "inner code", err: Wrap(fmt.Errorf("outer: %w", inner)), want: "inner_code"},
+		{name: "outer wins", err: WithCode(Wrap(fmt.Errorf("outer: %w", inner)), "outer_code"), want: "outer_code"},
+		{name: "joined", err: Join(errors.New("std"), inner), want: "inner_code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+	wrapped := WithCode(errors.New("std"), "std_code")
+	if Code(wrapped) != "std_code" || wrapped.Error() != "std" {
+		t.Errorf("WithCode(std) = %v with code %q", wrapped, Code(wrapped))
+	}
+	if st := wrapped.(*customError).stack; len(st) == 0 || st[0].function != "github.com/ice-coldbell/errorx.TestCode" {
+		t.Errorf("WithCode(std) stack = %v, want it to start at the caller", st)
+	}
+	if WithCode(nil, "code") != nil {
+		t.Error("WithCode(nil) != nil")
+	}
+
+	var nilErr *customError
+	if got := WithCode(nilErr, "code"); got != nil {
+		t.Errorf("WithCode(nil) = %v, want nil", got)
+	}
+}
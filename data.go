@@ -0,0 +1,58 @@
+package errorx
+
+// Key is a typed key for values attached to an error's data.
+type Key[T any] struct {
+	name string
+}
+
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Set attaches v to err under the key, wrapping err if it is not a
+// CustomError yet.
+func (k Key[T]) Set(err error, v T) CustomError {
+	if err == nil {
+		return nil
+	}
+	return WrapDepth(err, 4).With(k.name, v)
+}
+
+// Get returns the value stored under the key in the outermost layer of the
+// wrap chain holding a value of type T. Values set with WithSensitive are
+// returned unredacted.
+func (k Key[T]) Get(err error) (T, bool) {
+	var (
+		v     T
+		found bool
+	)
+	walkCustom(err, func(e *customError) bool {
+		data := e.data[k.name]
+		v, found = data.(T)
+		if r, ok := data.(interface{ unredacted() any }); ok && !found {
+			v, found = r.unredacted().(T)
+		}
+		return !found
+	})
+	return v, found
+}
+
+// Data merges the data of every CustomError in the wrap chain. When several
+// layers use the same key the outer layer wins; joined errors are merged in
+// order, so earlier members win over later ones.
+func Data(err error) map[string]any {
+	data := make(map[string]any)
+	walkCustom(err, func(e *customError) bool {
+		for k, v := range e.data {
+			if _, ok := data[k]; !ok {
+				data[k] = v
+			}
+		}
+		return true
+	})
+	return data
+}
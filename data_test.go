@@ -0,0 +1,73 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	userID := NewKey[int]("user_id")
+	name := NewKey[string]("name")
+
+	inner := userID.Set(errors.New("inner"), 1)
+	outer := userID.Set(fmt.Errorf("outer: %w", inner), 2)
+	mismatch := New("mismatch").With("user_id", "not an int")
+	sensitive := WithSensitive(New("sensitive"), "user_id", 3)
+
+	tests := []struct {
+		name   string
+		key    Key[int]
+		err    error
+		want   int
+		wantOK bool
+	}{
+		{name: "nil error", key: userID, err: nil},
+		{name: "single layer", key: userID, err: inner, want: 1, wantOK: true},
+		{name: "outer wins", key: userID, err: outer, want: 2, wantOK: true},
+		{name: "through fmt wrap", key: userID, err: fmt.Errorf("x: %w", inner), want: 1, wantOK: true},
+		{name: "type mismatch", key: userID, err: mismatch},
+		{name: "sensitive", key: userID, err: sensitive, want: 3, wantOK: true},
+		{name: "missing key", key: NewKey[int]("missing"), err: outer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.key.Get(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Key.Get() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got := name.Set(nil, "x"); got != nil {
+		t.Errorf("Key.Set(nil) = %v, want nil", got)
+	}
+	if got := name.Name(); got != "name" {
+		t.Errorf("Key.Name() = %v, want name", got)
+	}
+}
+
+func TestData(t *testing.T) {
+	inner := New("inner").WithData(map[string]any{"a": 1, "b": 1})
+	outer := WrapWithData(fmt.Errorf("outer: %w", inner), map[string]any{"b": 2, "c": 2})
+	joined := Join(New("first").With("a", "first"), New("second").With("a", "second").With("d", 4))
+
+	tests := []struct {
+		name string
+		err  error
+		want map[string]any
+	}{
+		{name: "nil error", err: nil, want: map[string]any{}},
+		{name: "std error", err: errors.New("std"), want: map[string]any{}},
+		{name: "outer wins", err: outer, want: map[string]any{"a": 1, "b": 2, "c": 2}},
+		{name: "joined in order", err: joined, want: map[string]any{"a": "first", "d": 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Data(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Data() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
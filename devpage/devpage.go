@@ -0,0 +1,233 @@
+// Package devpage renders errors as standalone HTML pages for development.
+//
+// The page shows internal messages, data and source code. Only enable it in
+// development.
+package devpage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+// Enabled turns on the developer page in Write. It is off by default so a
+// forgotten call cannot leak internals in production.
+var Enabled = false
+
+// SensitiveHeaders are request headers whose values are replaced by
+// errorx.RedactedPlaceholder on the page.
+var SensitiveHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie"}
+
+// Options configures Render.
+type Options struct {
+	// Request adds the method, URL, remote address and headers of the
+	// request that failed.
+	Request *http.Request
+	// SourceLines is the number of source lines shown before and after the
+	// line of each frame. Zero shows no source. The page is built from the
+	// JSON encoding of the error, whose frames carry no program counter, so
+	// source is read from the file paths left by the stack filters. With
+	// errorx.TrimPaths set, files outside the working directory are not
+	// found and their snippets are omitted.
+	SourceLines int
+	// Modules limits source snippets to frames from packages within these
+	// module paths. Empty means the main module of the running binary.
+	Modules []string
+}
+
+// Write writes err as a response with status 500. With Enabled, the body is
+// the developer page with the request details and five lines of source
+// around each frame. Otherwise it is errorx.Public(err) as JSON. A nil err
+// writes nothing. The returned error is the one of writing the body.
+func Write(w http.ResponseWriter, r *http.Request, err error) error {
+	if err == nil {
+		return nil
+	}
+	if !Enabled {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return errorx.Wrap(json.NewEncoder(w).Encode(errorx.Public(err)))
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	return Render(w, err, Options{Request: r, SourceLines: 5})
+}
+
+// Render writes err as an HTML page: its message and code, data, the
+// expandable chain of causes with their stacks, and every joined error.
+func Render(w io.Writer, err error, opts Options) error {
+	if len(opts.Modules) == 0 {
+		if info, ok := readBuildInfo(); ok && info.Main.Path != "" {
+			opts.Modules = []string{info.Main.Path}
+		}
+	}
+	p := page{}
+	if err != nil {
+		v, verr := opts.view(err)
+		if verr != nil {
+			return verr
+		}
+		p.Error = v
+		p.Title = v.Message
+	}
+	if opts.Request != nil {
+		p.Request = newRequestView(opts.Request)
+	}
+	return errorx.Wrap(pageTemplate.Execute(w, p))
+}
+
+//go:embed page.html
+var pageHTML string
+
+var pageTemplate = template.Must(template.New("page").Parse(pageHTML))
+
+// for test injection
+var readBuildInfo = debug.ReadBuildInfo
+
+type page struct {
+	Title   string
+	Error   *errorView
+	Request *requestView
+}
+
+type errorView struct {
+	Message string
+	Code    string
+	Data    []field
+	Frames  []frameView
+	// Cause is the next structured error in the wrap chain.
+	Cause *errorView
+	// Joined are the members of a joined error.
+	Joined []*errorView
+}
+
+type field struct {
+	Key   string
+	Value string
+}
+
+type frameView struct {
+	Function string
+	File     string
+	Line     int
+	Source   []errorx.SourceLine
+}
+
+type requestView struct {
+	Method     string
+	URL        string
+	RemoteAddr string
+	Headers    []field
+}
+
+// view converts err through its JSON encoding, which has the message, code,
+// data and filtered stack of each layer, a single cause for a wrapped error
+// and every member for a joined one.
+//
+// Other errors are wrapped with errorx.Wrap first, which finds errorx errors
+// behind fmt.Errorf and other plain wrappers. The wrapper's own stack points
+// here and is dropped.
+func (opts *Options) view(err error) (*errorView, error) {
+	_, marshaler := err.(json.Marshaler)
+	if !marshaler {
+		err = errorx.Wrap(err)
+	}
+	b, err := json.Marshal(err)
+	if err != nil {
+		return nil, errorx.Wrap(err)
+	}
+	return opts.viewJSON(b, marshaler)
+}
+
+// viewJSON converts one encoded layer. The layer is decoded without its
+// causes, so errorx.Code, errorx.Data and errorx.StackOf describe it alone.
+func (opts *Options) viewJSON(b []byte, withStack bool) (*errorView, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, errorx.Wrap(err)
+	}
+	if !withStack {
+		delete(fields, "stack")
+	}
+	var causes []json.RawMessage
+	if raw, ok := fields["causes"]; ok {
+		if err := json.Unmarshal(raw, &causes); err != nil {
+			return nil, errorx.Wrap(err)
+		}
+		delete(fields, "causes")
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, errorx.Wrap(err)
+	}
+	e, err := errorx.FromJSON(b)
+	if err != nil {
+		return nil, errorx.Wrap(err)
+	}
+
+	v := &errorView{Message: e.Error(), Code: errorx.Code(e)}
+	for k, val := range errorx.Data(e) {
+		v.Data = append(v.Data, field{Key: k, Value: formatValue(val)})
+	}
+	sort.Slice(v.Data, func(i, j int) bool { return v.Data[i].Key < v.Data[j].Key })
+	snippets := errorx.RenderOptions{SourceLines: opts.SourceLines, Modules: opts.Modules}
+	for _, f := range errorx.StackOf(e) {
+		v.Frames = append(v.Frames, frameView{
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+			Source:   snippets.Snippet(f),
+		})
+	}
+
+	for _, raw := range causes {
+		cause, err := opts.viewJSON(raw, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(causes) == 1 {
+			v.Cause = cause
+		} else {
+			v.Joined = append(v.Joined, cause)
+		}
+	}
+	return v, nil
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func newRequestView(r *http.Request) *requestView {
+	v := &requestView{
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		RemoteAddr: r.RemoteAddr,
+	}
+	for k, values := range r.Header {
+		value := strings.Join(values, ", ")
+		for _, s := range SensitiveHeaders {
+			if http.CanonicalHeaderKey(s) == k {
+				value = errorx.RedactedPlaceholder
+			}
+		}
+		v.Headers = append(v.Headers, field{Key: k, Value: value})
+	}
+	sort.Slice(v.Headers, func(i, j int) bool { return v.Headers[i].Key < v.Headers[j].Key })
+	return v
+}
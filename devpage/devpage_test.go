@@ -0,0 +1,139 @@
+package devpage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func readError(t *testing.T, name string) error {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fromJSON(t, string(b))
+}
+
+func fromJSON(t *testing.T, s string) errorx.CustomError {
+	t.Helper()
+	e, err := errorx.FromJSON([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestRender(t *testing.T) {
+	t.Cleanup(func() { readBuildInfo = debug.ReadBuildInfo })
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Main: debug.Module{Path: "example.com/app"}}, true
+	}
+
+	req := httptest.NewRequest("POST", "http://example.com/items?id=", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("User-Agent", "test")
+
+	tests := []struct {
+		name string
+		err  error
+		opts Options
+	}{
+		{name: "wrapped", err: readError(t, "wrapped.json"), opts: Options{SourceLines: 2}},
+		{name: "fmt_wrapped", err: fmt.Errorf("get user: %w", readError(t, "wrapped.json"))},
+		{name: "joined", err: errorx.Join(
+			fromJSON(t, `{"message": "first", "code": "a", "stack": ["example.com/app.load testdata/app.go:5"]}`),
+			fromJSON(t, `{"message": "second", "code": "b"}`),
+		)},
+		{name: "request", err: errors.New("std"), opts: Options{Request: req}},
+		{name: "nil", err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Render(&b, tt.err, tt.opts); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden.html")
+			if *update {
+				if err := os.WriteFile(golden, b.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != string(want) {
+				t.Errorf("Render() = %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Cleanup(func() { Enabled = false })
+	err := errorx.WithCode(errorx.New("internal detail"), "not_found")
+	req := httptest.NewRequest("GET", "/", nil)
+
+	rec := httptest.NewRecorder()
+	Write(rec, req, err)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	var public errorx.PublicError
+	if jerr := json.Unmarshal(rec.Body.Bytes(), &public); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if public.Code != "not_found" || public.Message != errorx.DefaultPublicMessage {
+		t.Errorf("disabled Write() body = %+v", public)
+	}
+
+	Enabled = true
+	rec = httptest.NewRecorder()
+	Write(rec, req, err)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "<h1>internal detail</h1>") {
+		t.Errorf("enabled Write() body = %s", rec.Body)
+	}
+}
+
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestWrite_errors(t *testing.T) {
+	t.Cleanup(func() { Enabled = false })
+	req := httptest.NewRequest("GET", "/", nil)
+
+	rec := httptest.NewRecorder()
+	if err := Write(rec, req, nil); err != nil || rec.Body.Len() != 0 || rec.Code != http.StatusOK {
+		t.Errorf("Write(nil) = %v, wrote %d %q; want nothing", err, rec.Code, rec.Body)
+	}
+
+	for _, enabled := range []bool{false, true} {
+		Enabled = enabled
+		if err := Write(failingWriter{httptest.NewRecorder()}, req, errorx.New("boom")); err == nil {
+			t.Errorf("Write() with Enabled = %v to a failing writer error = nil", enabled)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package app
+
+func load(id string) error {
+	if id == "" {
+		return errorx.New("empty id")
+	}
+	return nil
+}
+
+func handle(id string) error {
+	return errorx.WithCode(errorx.Wrap(load(id)), "bad_request")
+}
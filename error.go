@@ -2,13 +2,22 @@ package errorx
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 )
 
 type customError struct {
-	err   error
-	stack stack
-	data  map[string]any
+	err           error
+	stack         stack
+	data          map[string]any
+	code          string
+	publicMessage string
+	retryable     *bool
+	retryAfter    time.Duration
+	fingerprint   string
+	hint          string
+	exitCode      *int
 }
 
 func New(message string) CustomError {
@@ -19,6 +28,16 @@ func New(message string) CustomError {
 	}
 }
 
+// Errorf formats like fmt.Errorf, so %w wraps its operand, and records the
+// stack of the caller.
+func Errorf(format string, args ...any) CustomError {
+	return &customError{
+		err:   fmt.Errorf(format, args...),
+		stack: callers(3),
+		data:  make(map[string]any),
+	}
+}
+
 func (e *customError) Error() string {
 	if e == nil || e.err == nil {
 		return ""
@@ -51,12 +70,77 @@ func (e *customError) WithData(data map[string]any) CustomError {
 	return e
 }
 
+func (e *customError) withSensitive(key string, data any) CustomError {
+	return e.With(key, Redact(data))
+}
+
+func (e *customError) withCode(code string) CustomError {
+	if e == nil || e.err == nil {
+		return nil
+	}
+	e.code = code
+	return e
+}
+
+func (e *customError) withPublicMessage(message string) CustomError {
+	if e == nil || e.err == nil {
+		return nil
+	}
+	e.publicMessage = message
+	return e
+}
+
+func (e *customError) withRetryable(retryable bool) CustomError {
+	if e == nil || e.err == nil {
+		return nil
+	}
+	e.retryable = &retryable
+	return e
+}
+
+// withRetryAfter marks the error retryable after at least d.
+func (e *customError) withRetryAfter(d time.Duration) CustomError {
+	if e == nil || e.err == nil {
+		return nil
+	}
+	e.retryAfter = d
+	return e.withRetryable(true)
+}
+
+// withFingerprint overrides the fingerprint computed by Fingerprint.
+func (e *customError) withFingerprint(fingerprint string) CustomError {
+	if e == nil || e.err == nil {
+		return nil
+	}
+	e.fingerprint = fingerprint
+	return e
+}
+
+// withHint adds a suggestion for the user on how to resolve the error.
+func (e *customError) withHint(hint string) CustomError {
+	if e == nil || e.err == nil {
+		return nil
+	}
+	e.hint = hint
+	return e
+}
+
+// withExitCode sets the process exit code used by Exit and ExitCode. As
+// with WithExitCode, a code of 0 counts as unset.
+func (e *customError) withExitCode(code int) CustomError {
+	if e == nil || e.err == nil {
+		return nil
+	}
+	e.exitCode = &code
+	return e
+}
+
 // For sentry-go extract stacktrace
 func (e *customError) StackTrace() []uintptr {
 	if e == nil || e.err == nil {
 		return nil
 	}
-	return e.stack.StackTrace()
+	return e.stack.filtered().StackTrace()
 }
 
 func (e *customError) Unwrap() error {
@@ -70,8 +154,14 @@ func (e *customError) LogValue() slog.Value {
 	attr := []slog.Attr{
 		slog.String("message", e.Error()),
 	}
+	if e.code != "" {
+		attr = append(attr, slog.String("code", e.code))
+	}
 	for k, v := range e.data {
 		attr = append(attr, slog.Any(k, v))
 	}
+	if LogStack && len(e.stack) > 0 {
+		attr = append(attr, slog.Any("stack", e.stack))
+	}
 	return slog.GroupValue(attr...)
 }
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"reflect"
+	"runtime"
 	"testing"
 )
 
@@ -54,6 +55,35 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestErrorf(t *testing.T) {
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+	inner := WithCode(New("not found"), "not_found")
+	tests := []struct {
+		name    string
+		err     CustomError
+		want    string
+		wrapped error
+	}{
+		{name: "plain", err: Errorf("user %d", 7), want: "user 7"},
+		{name: "wrap", err: Errorf("load user %d: %w", 7, inner), want: "load user 7: not found", wrapped: inner},
+		{name: "verb v", err: Errorf("load: %v", inner), want: "load: not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Errorf().Error() = %q, want %q", got, tt.want)
+			}
+			if got := errors.Is(tt.err, inner); got != (tt.wrapped != nil) {
+				t.Errorf("errors.Is(Errorf(), inner) = %v", got)
+			}
+			if f := tt.err.(*customError).stack[0].export().Function; f != "github.com/ice-coldbell/errorx.TestErrorf" {
+				t.Errorf("Errorf() stack starts at %s", f)
+			}
+		})
+	}
+}
+
 func Test_customError_Error(t *testing.T) {
 	runtimeCallers = func(skip int, pc []uintptr) int {
 		pc[0] = globalTestPC
@@ -0,0 +1,13 @@
+// Command errorxlint reports common misuse of errorx.
+//
+//	go vet -vettool=$(which errorxlint) ./...
+package main
+
+import (
+	"github.com/ice-coldbell/errorx/errorxlint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(errorxlint.Analyzer)
+}
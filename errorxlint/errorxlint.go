@@ -0,0 +1,321 @@
+// Package errorxlint defines an analyzer that reports common misuse of
+// errorx.
+//
+// It runs with go vet through cmd/errorxlint:
+//
+//	go vet -vettool=$(which errorxlint) ./...
+//
+// and can be registered in golangci-lint as a plugin from Analyzer.
+package errorxlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const errorxPath = "github.com/ice-coldbell/errorx"
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorxlint",
+	Doc:      "report common misuse of errorx",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodes := []ast.Node{(*ast.ExprStmt)(nil), (*ast.CallExpr)(nil), (*ast.FuncDecl)(nil)}
+	inspect.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.ExprStmt:
+			checkDiscardedWith(pass, n)
+		case *ast.CallExpr:
+			fn := typeutil.StaticCallee(pass.TypesInfo, n)
+			switch {
+			case isErrorxFunc(fn, "Wrap"):
+				checkWrapCustomError(pass, n)
+			case isErrorxFunc(fn, "Join"):
+				checkJoinInterface(pass, n, stack)
+			case isFunc(fn, "fmt", "Errorf"):
+				checkErrorfVerbs(pass, n)
+			}
+		case *ast.FuncDecl:
+			checkThirdPartyReturns(pass, n)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+func isFunc(fn *types.Func, pkg, name string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == pkg && fn.Name() == name
+}
+
+func isErrorxFunc(fn *types.Func, name string) bool {
+	return isFunc(fn, errorxPath, name)
+}
+
+func isCustomError(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == errorxPath && named.Obj().Name() == "CustomError"
+}
+
+// checkDiscardedWith reports With calls whose result is dropped. The
+// methods return nil for a CustomError wrapping a nil error and the
+// package-level functions wrap errors that are not CustomErrors yet, so the
+// result must be used in place of the argument.
+func checkDiscardedWith(pass *analysis.Pass, stmt *ast.ExprStmt) {
+	call, ok := stmt.X.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.HasPrefix(sel.Sel.Name, "With") {
+		return
+	}
+	if fn := typeutil.StaticCallee(pass.TypesInfo, call); isErrorxFunc(fn, sel.Sel.Name) {
+		pass.Reportf(call.Pos(), "result of %s is not used; it is a new error when err is not a CustomError", sel.Sel.Name)
+		return
+	}
+	if !isCustomError(pass.TypesInfo.TypeOf(sel.X)) {
+		return
+	}
+	pass.Reportf(call.Pos(), "result of %s is not used; it is nil when the error is nil", sel.Sel.Name)
+}
+
+// checkWrapCustomError reports Wrap of a value that is statically a
+// CustomError, which Wrap returns unchanged.
+func checkWrapCustomError(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) != 1 {
+		return
+	}
+	if isCustomError(pass.TypesInfo.TypeOf(call.Args[0])) {
+		pass.Reportf(call.Pos(), "Wrap of a CustomError returns it unchanged")
+	}
+}
+
+// checkJoinInterface reports Join results converted to an interface. Join
+// returns a nil slice when it joins no errors, which becomes a non-nil
+// interface value.
+func checkJoinInterface(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+	if len(stack) < 2 {
+		return
+	}
+	var target types.Type
+	switch parent := stack[len(stack)-2].(type) {
+	case *ast.ReturnStmt:
+		sig := enclosingSignature(pass, stack)
+		for i, r := range parent.Results {
+			if r == call && sig != nil && sig.Results().Len() == len(parent.Results) {
+				target = sig.Results().At(i).Type()
+			}
+		}
+	case *ast.AssignStmt:
+		for i, r := range parent.Rhs {
+			if r == call && len(parent.Lhs) == len(parent.Rhs) {
+				target = pass.TypesInfo.TypeOf(parent.Lhs[i])
+			}
+		}
+	case *ast.ValueSpec:
+		if parent.Type != nil {
+			target = pass.TypesInfo.TypeOf(parent.Type)
+		}
+	case *ast.CallExpr:
+		sig, ok := pass.TypesInfo.TypeOf(parent.Fun).(*types.Signature)
+		if !ok {
+			return
+		}
+		for i, arg := range parent.Args {
+			if arg == call {
+				target = paramType(sig, i)
+			}
+		}
+	}
+	if target != nil && types.IsInterface(target) {
+		pass.Reportf(call.Pos(), "Join result stored as %s is non-nil even when no errors are joined", types.TypeString(target, types.RelativeTo(pass.Pkg)))
+	}
+}
+
+func paramType(sig *types.Signature, i int) types.Type {
+	params := sig.Params()
+	if sig.Variadic() && i >= params.Len()-1 {
+		return params.At(params.Len() - 1).Type().(*types.Slice).Elem()
+	}
+	if i < params.Len() {
+		return params.At(i).Type()
+	}
+	return nil
+}
+
+func enclosingSignature(pass *analysis.Pass, stack []ast.Node) *types.Signature {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncLit:
+			sig, _ := pass.TypesInfo.TypeOf(fn).(*types.Signature)
+			return sig
+		case *ast.FuncDecl:
+			if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+				return obj.Type().(*types.Signature)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// checkErrorfVerbs reports errors formatted by fmt.Errorf with a verb
+// other than %w, which loses the error chain.
+func checkErrorfVerbs(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) < 2 {
+		return
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+	verbs, ok := formatVerbs(constant.StringVal(tv.Value))
+	if !ok {
+		return
+	}
+	for i, arg := range call.Args[1:] {
+		if i >= len(verbs) || verbs[i] == 'w' {
+			continue
+		}
+		if t := pass.TypesInfo.TypeOf(arg); t != nil && types.Implements(t, errorType) {
+			pass.Reportf(arg.Pos(), "error formatted with %%%c loses the error chain; use %%w", verbs[i])
+		}
+	}
+}
+
+// formatVerbs returns the verbs of format in argument order. It gives up
+// on explicit argument indexes and * widths.
+func formatVerbs(format string) ([]rune, bool) {
+	var verbs []rune
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format); i++ {
+			c := format[i]
+			if c == '[' || c == '*' {
+				return nil, false
+			}
+			if strings.IndexByte("+-# 0.123456789", c) < 0 {
+				break
+			}
+		}
+		if i < len(format) && format[i] != '%' {
+			verbs = append(verbs, rune(format[i]))
+		}
+	}
+	return verbs, true
+}
+
+// checkThirdPartyReturns reports exported functions returning an error
+// straight from a third-party package, without errorx.Wrap or %w context.
+func checkThirdPartyReturns(pass *analysis.Pass, decl *ast.FuncDecl) {
+	if decl.Body == nil || !decl.Name.IsExported() {
+		return
+	}
+
+	// fromThirdParty holds the variables only ever assigned from
+	// third-party calls.
+	fromThirdParty := make(map[types.Object]bool)
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		third := len(assign.Rhs) == 1 && isThirdPartyCall(pass, assign.Rhs[0])
+		for _, lhs := range assign.Lhs {
+			id, ok := lhs.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			obj := pass.TypesInfo.ObjectOf(id)
+			if obj == nil || !types.Identical(obj.Type(), types.Universe.Lookup("error").Type()) {
+				continue
+			}
+			if prev, seen := fromThirdParty[obj]; seen && !prev {
+				continue
+			}
+			fromThirdParty[obj] = third
+		}
+		return true
+	})
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok {
+			return true
+		}
+		for _, r := range ret.Results {
+			switch r := r.(type) {
+			case *ast.Ident:
+				if fromThirdParty[pass.TypesInfo.ObjectOf(r)] {
+					pass.Reportf(r.Pos(), "error from a third-party package returned across the package boundary; wrap it with errorx.Wrap")
+				}
+			case *ast.CallExpr:
+				if isThirdPartyCall(pass, r) && types.Identical(pass.TypesInfo.TypeOf(r), types.Universe.Lookup("error").Type()) {
+					pass.Reportf(r.Pos(), "error from a third-party package returned across the package boundary; wrap it with errorx.Wrap")
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isThirdPartyCall reports whether expr calls a function from a package
+// outside the standard library, errorx and the current module.
+func isThirdPartyCall(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn := typeutil.Callee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil {
+		return false
+	}
+	path := fn.Pkg().Path()
+	if path == errorxPath || strings.HasPrefix(path, errorxPath+"/") {
+		return false
+	}
+	first, _, _ := strings.Cut(path, "/")
+	if !strings.Contains(first, ".") {
+		return false
+	}
+	if pass.Module != nil && pass.Module.Path != "" {
+		return path != pass.Module.Path && !strings.HasPrefix(path, pass.Module.Path+"/")
+	}
+	return modulePrefix(path) != modulePrefix(pass.Pkg.Path())
+}
+
+// modulePrefix approximates the module path of a package by its first
+// three path elements, as in "github.com/owner/repo". It is used when the
+// driver does not report the module of the package.
+func modulePrefix(path string) string {
+	parts := strings.SplitN(path, "/", 4)
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,37 @@
+package errorxlint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestAnalyzer_module(t *testing.T) {
+	analysistest.Run(t, filepath.Join(analysistest.TestData(), "mod"), Analyzer, "./...")
+}
+
+func Test_formatVerbs(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+		ok     bool
+	}{
+		{format: "no verbs", want: "", ok: true},
+		{format: "%v %w", want: "vw", ok: true},
+		{format: "%5.2f%% %-10s %+v %#x", want: "fsvx", ok: true},
+		{format: "%[1]v", ok: false},
+		{format: "%*d", ok: false},
+		{format: "trailing %", want: "", ok: true},
+	}
+	for _, tt := range tests {
+		got, ok := formatVerbs(tt.format)
+		if ok != tt.ok || string(got) != tt.want {
+			t.Errorf("formatVerbs(%q) = %q, %v, want %q, %v", tt.format, string(got), ok, tt.want, tt.ok)
+		}
+	}
+}
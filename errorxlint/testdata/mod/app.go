@@ -0,0 +1,7 @@
+package app
+
+import "example.com/app/store"
+
+func Load() error {
+	return store.Get() // ok: same module
+}
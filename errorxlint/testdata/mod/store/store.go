@@ -0,0 +1,5 @@
+package store
+
+import "errors"
+
+func Get() error { return errors.New("not found") }
@@ -0,0 +1,70 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+
+	"example.com/vendorlib"
+	"github.com/ice-coldbell/errorx"
+)
+
+func discarded(err error) {
+	cerr := errorx.Wrap(err)
+	cerr.With("k", 1)                     // want `result of With is not used; it is nil when the error is nil`
+	cerr.WithData(map[string]any{"k": 1}) // want `result of WithData is not used`
+	cerr = cerr.With("k", 1)              // ok
+	_ = errorx.WithCode(err, "code")      // ok
+	errorx.WithCode(err, "code")          // want `result of WithCode is not used; it is a new error when err is not a CustomError`
+	fmt.Println(cerr)
+}
+
+func wrapCustom(cerr errorx.CustomError, err error) {
+	_ = errorx.Wrap(cerr)            // want `Wrap of a CustomError returns it unchanged`
+	_ = errorx.Wrap(errorx.New("x")) // want `Wrap of a CustomError returns it unchanged`
+	_ = errorx.Wrap(err)             // ok
+}
+
+func joinReturn(errs []error) error {
+	return errorx.Join(errs...) // want `Join result stored as error is non-nil even when no errors are joined`
+}
+
+func joinAssign(errs []error) {
+	var err error
+	err = errorx.Join(errs...)     // want `Join result stored as error`
+	var err2 error = errorx.Join() // want `Join result stored as error`
+	joined := errorx.Join(errs...) // ok
+	fmt.Println(err, err2, len(joined))
+	fmt.Println(errorx.Join(errs...)) // want `Join result stored as any`
+}
+
+func errorf(err error) error {
+	_ = fmt.Errorf("a: %v", err)              // want `error formatted with %v loses the error chain; use %w`
+	_ = fmt.Errorf("a %d: %s", 1, err)        // want `error formatted with %s loses the error chain`
+	_ = fmt.Errorf("a %5.2f%%: %w", 1.0, err) // ok
+	_ = fmt.Errorf("a %[1]v", err)            // ok: explicit index is not checked
+	return fmt.Errorf("a: %w", err)
+}
+
+func Exported() error {
+	if err := vendorlib.Do(); err != nil {
+		return err // want `error from a third-party package returned across the package boundary`
+	}
+	_, err := vendorlib.Value()
+	if err != nil {
+		return errorx.Wrap(err) // ok
+	}
+	return vendorlib.Do() // want `error from a third-party package returned across the package boundary`
+}
+
+func ExportedWrapped() error {
+	err := vendorlib.Do()
+	if err != nil {
+		err = fmt.Errorf("do: %w", err)
+		return err // ok: reassigned with context
+	}
+	return errors.New("std") // ok
+}
+
+func unexported() error {
+	return vendorlib.Do() // ok: not across the package boundary
+}
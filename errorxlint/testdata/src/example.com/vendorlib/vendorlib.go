@@ -0,0 +1,5 @@
+package vendorlib
+
+func Do() error { return nil }
+
+func Value() (int, error) { return 0, nil }
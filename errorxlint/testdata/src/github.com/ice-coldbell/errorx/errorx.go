@@ -0,0 +1,20 @@
+// Package errorx is a stub of the errorx API used by the analyzer tests.
+package errorx
+
+type CustomError interface {
+	error
+	With(key string, value any) CustomError
+	WithData(map[string]any) CustomError
+}
+
+type customErrors []CustomError
+
+func (e customErrors) Error() string { return "" }
+
+func New(message string) CustomError { return nil }
+
+func Wrap(err error) CustomError { return nil }
+
+func WithCode(err error, code string) CustomError { return nil }
+
+func Join(errs ...error) customErrors { return nil }
@@ -0,0 +1,35 @@
+// Package errorxmultierr registers the multi-errors of go.uber.org/multierr
+// and github.com/hashicorp/go-multierror with errorx, so errorx.Join
+// flattens them and functions such as errorx.Code inspect their members:
+//
+//	import _ "github.com/ice-coldbell/errorx/errorxmultierr"
+package errorxmultierr
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/ice-coldbell/errorx"
+	"go.uber.org/multierr"
+)
+
+// multierrType is the unexported type multierr.Combine returns for two or
+// more errors.
+var multierrType = reflect.TypeOf(multierr.Combine(errors.New("a"), errors.New("b")))
+
+func init() {
+	errorx.RegisterMultiError(Members)
+}
+
+// Members returns the members of a *multierror.Error or of a multi-error
+// built by go.uber.org/multierr. It reports false for any other error.
+func Members(err error) ([]error, bool) {
+	if e, ok := err.(*multierror.Error); ok {
+		return e.WrappedErrors(), true
+	}
+	if reflect.TypeOf(err) == multierrType {
+		return multierr.Errors(err), true
+	}
+	return nil, false
+}
@@ -0,0 +1,64 @@
+package errorxmultierr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/ice-coldbell/errorx"
+	"go.uber.org/multierr"
+)
+
+func TestJoin(t *testing.T) {
+	errStd := errors.New("std")
+	errCustom := errorx.WithCode(errorx.New("custom"), "c")
+	uber := multierr.Combine(errStd, errCustom)
+	hashi := multierror.Append(errors.New("a"), errors.New("b"))
+
+	got := errorx.Join(uber, hashi, errorx.Join(errors.New("c")))
+	if want := "std\ncustom\na\nb\nc"; got.Error() != want {
+		t.Errorf("Join().Error() = %q, want %q", got.Error(), want)
+	}
+	members, ok := errorx.JoinedErrors(got)
+	if !ok || len(members) != 5 {
+		t.Fatalf("Join() has %d members, want 5", len(members))
+	}
+	if members[1] != errCustom {
+		t.Errorf("Join()[1] = %#v, want the custom error unchanged", members[1])
+	}
+	for i, member := range members {
+		if st := errorx.StackOf(member); len(st) == 0 || st[0].Function != "github.com/ice-coldbell/errorx/errorxmultierr.TestJoin" {
+			t.Errorf("Join()[%d] stack = %v", i, st)
+		}
+	}
+	if !errors.Is(got, errStd) {
+		t.Error("errors.Is(Join(), errStd) = false")
+	}
+
+	// multierr's errorGroup interface.
+	var group interface{ Errors() []error } = got
+	if members := group.Errors(); len(members) != 5 || members[1] != errCustom {
+		t.Errorf("Join().Errors() = %v", members)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	hashi := multierror.Append(errors.New("a"), errorx.WithCode(errorx.New("b"), "b_code"))
+	if got := errorx.Code(fmt.Errorf("wrap: %w", hashi)); got != "b_code" {
+		t.Errorf("Code() = %q, want %q", got, "b_code")
+	}
+	uber := multierr.Combine(errors.New("a"), errorx.WithExitCode(errorx.New("b"), 3))
+	if got := errorx.ExitCode(uber); got != 3 {
+		t.Errorf("ExitCode() = %d, want 3", got)
+	}
+}
+
+func TestMembers(t *testing.T) {
+	if _, ok := Members(multierr.Combine(errors.New("a"))); ok {
+		t.Error("Members() of a single combined error = true, want false")
+	}
+	if _, ok := Members(errors.Join(errors.New("a"), errors.New("b"))); ok {
+		t.Error("Members(errors.Join()) = true, want false")
+	}
+}
@@ -0,0 +1,66 @@
+// Package errorxpkgerrors adapts errorx errors to the interfaces of
+// github.com/pkg/errors for integrations that only understand those, such
+// as older Sentry clients and logging hooks.
+//
+// errorx errors already implement the pkg/errors causer interface. Their
+// StackTrace method returns []uintptr for sentry-go and cannot also return
+// errors.StackTrace, so Wrap adds a wrapper that does:
+//
+//	hook.Fire(errorxpkgerrors.Wrap(err))
+package errorxpkgerrors
+
+import (
+	"fmt"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/pkg/errors"
+)
+
+// Wrap returns err with a StackTrace() errors.StackTrace method and a
+// Cause() error method returning err. It returns nil for a nil err.
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	if w, ok := err.(*wrapped); ok {
+		return w
+	}
+	return &wrapped{err: err}
+}
+
+// StackTrace returns the deepest stack captured in err's wrap chain as an
+// errors.StackTrace. It is built from the program counters returned by
+// errorx.StackPCs, so stack filters, which may rewrite or drop frames, do
+// not apply.
+func StackTrace(err error) errors.StackTrace {
+	pcs := errorx.StackPCs(err)
+	if len(pcs) == 0 {
+		return nil
+	}
+	out := make(errors.StackTrace, len(pcs))
+	for i, pc := range pcs {
+		out[i] = errors.Frame(pc)
+	}
+	return out
+}
+
+type wrapped struct {
+	err error
+}
+
+func (w *wrapped) Error() string { return w.err.Error() }
+
+func (w *wrapped) Unwrap() error { return w.err }
+
+func (w *wrapped) Cause() error { return w.err }
+
+func (w *wrapped) StackTrace() errors.StackTrace { return StackTrace(w.err) }
+
+// Format formats like the wrapped error, so %+v keeps the errorx output.
+func (w *wrapped) Format(s fmt.State, verb rune) {
+	if f, ok := w.err.(fmt.Formatter); ok {
+		f.Format(s, verb)
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), w.err)
+}
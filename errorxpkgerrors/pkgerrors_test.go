@@ -0,0 +1,69 @@
+package errorxpkgerrors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/pkg/errors"
+)
+
+func newError() error {
+	return errorx.WithCode(errorx.New("not found"), "not_found")
+}
+
+func TestWrap(t *testing.T) {
+	inner := newError()
+	err := Wrap(fmt.Errorf("load: %w", inner))
+
+	st, ok := err.(interface{ StackTrace() errors.StackTrace })
+	if !ok {
+		t.Fatalf("Wrap() = %T, want a pkg/errors stackTracer", err)
+	}
+	frames := st.StackTrace()
+	if len(frames) == 0 {
+		t.Fatal("StackTrace() is empty")
+	}
+	if got := fmt.Sprintf("%n", frames[0]); got != "newError" {
+		t.Errorf("StackTrace()[0] = %s, want newError", got)
+	}
+	if got := fmt.Sprintf("%+s", frames[0]); !strings.Contains(got, "errorxpkgerrors.newError\n\t") || !strings.Contains(got, "pkgerrors_test.go") {
+		t.Errorf("StackTrace()[0] = %q, want function and file", got)
+	}
+
+	if got := errors.Cause(Wrap(inner)); got.Error() != "not found" || got == inner {
+		t.Errorf("errors.Cause() = %#v, want the error wrapped by errorx.New", got)
+	}
+	if !errorx.Is(err, inner) || errorx.Code(err) != "not_found" {
+		t.Errorf("Wrap() hides the chain of %v", inner)
+	}
+	if got, want := fmt.Sprintf("%+v", Wrap(inner)), fmt.Sprintf("%+v", inner); got != want {
+		t.Errorf("%%+v = %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%q", err); got != `"load: not found"` {
+		t.Errorf("%%q = %s", got)
+	}
+	if Wrap(err) != err {
+		t.Error("Wrap() wrapped twice")
+	}
+	if Wrap(nil) != nil {
+		t.Error("Wrap(nil) != nil")
+	}
+	if StackTrace(fmt.Errorf("plain")) != nil {
+		t.Error("StackTrace() of an error without stack is not nil")
+	}
+}
+
+func TestStackTrace_Filters(t *testing.T) {
+	defer errorx.SetStackFilters(errorx.StackFilters()...)
+	errorx.SetStackFilters(errorx.DropPackages("github.com/ice-coldbell/errorx/errorxpkgerrors"), errorx.TrimPaths())
+
+	frames := StackTrace(newError())
+	if len(frames) == 0 {
+		t.Fatal("StackTrace() is empty with stack filters set")
+	}
+	if got := fmt.Sprintf("%+s", frames[0]); !strings.Contains(got, "errorxpkgerrors.newError\n\t") {
+		t.Errorf("StackTrace()[0] = %q, want the unfiltered frame", got)
+	}
+}
@@ -0,0 +1,143 @@
+// Package errorxtest provides test assertions for errorx errors that only
+// rely on the public API, so they work from any package.
+package errorxtest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+// AssertCode checks that errorx.Code(err) is code.
+func AssertCode(t testing.TB, err error, code string) bool {
+	t.Helper()
+	if got := errorx.Code(err); got != code {
+		t.Errorf("errorx.Code(%v) = %q, want %q", err, got, code)
+		return false
+	}
+	return true
+}
+
+// AssertData checks that err carries want under key. Sensitive values are
+// compared unredacted.
+func AssertData(t testing.TB, err error, key string, want any) bool {
+	t.Helper()
+	got, ok := errorx.Unredacted(err)[key]
+	if !ok {
+		t.Errorf("error %q has no data %q", err, key)
+		return false
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("error %q data %q = %#v, want %#v", err, key, got, want)
+		return false
+	}
+	return true
+}
+
+// AssertStackContains checks that the stack returned by errorx.StackOf has
+// a frame of function, given either fully qualified or as "pkg.Func" or
+// "pkg.(*T).Method".
+func AssertStackContains(t testing.TB, err error, function string) bool {
+	t.Helper()
+	st := errorx.StackOf(err)
+	var functions []string
+	for _, f := range st {
+		if matchFunction(f.Function, function) {
+			return true
+		}
+		functions = append(functions, f.Function)
+	}
+	t.Errorf("stack of %q has no frame of %s; functions:\n\t%s", err, function, strings.Join(functions, "\n\t"))
+	return false
+}
+
+func matchFunction(full, name string) bool {
+	return full == name || strings.HasSuffix(full, "/"+name)
+}
+
+// AssertJoined checks that err is or wraps a joined error with n members.
+// Multi-errors registered with errorx.RegisterMultiError count as joined
+// errors.
+func AssertJoined(t testing.TB, err error, n int) bool {
+	t.Helper()
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		members, ok := errorx.JoinedErrors(cur)
+		if !ok {
+			continue
+		}
+		if got := len(members); got != n {
+			t.Errorf("error %q joins %d errors, want %d", err, got, n)
+			return false
+		}
+		return true
+	}
+	t.Errorf("error %q is not a joined error", err)
+	return false
+}
+
+// AssertEqual checks that got and want are Equal and reports the first
+// difference otherwise.
+func AssertEqual(t testing.TB, got, want error) bool {
+	t.Helper()
+	if d := diff(got, want, "err"); d != "" {
+		t.Errorf("errors differ: %s", d)
+		return false
+	}
+	return true
+}
+
+// Equal reports whether a and b have the same message, code and data at
+// every level of their cause chains, including joined errors. Stacks are
+// ignored.
+func Equal(a, b error) bool {
+	return diff(a, b, "err") == ""
+}
+
+// diff returns a description of the first difference between a and b, or
+// "" if they are equal. path names the current level for the description.
+func diff(a, b error, path string) string {
+	if a == nil || b == nil {
+		if a != b {
+			return fmt.Sprintf("%s: %v != %v", path, a, b)
+		}
+		return ""
+	}
+	if a.Error() != b.Error() {
+		return fmt.Sprintf("%s: message %q != %q", path, a.Error(), b.Error())
+	}
+	if ca, cb := errorx.Code(a), errorx.Code(b); ca != cb {
+		return fmt.Sprintf("%s: code %q != %q", path, ca, cb)
+	}
+	if da, db := errorx.Unredacted(a), errorx.Unredacted(b); !reflect.DeepEqual(da, db) {
+		return fmt.Sprintf("%s: data %v != %v", path, da, db)
+	}
+
+	ua, ub := unwrap(a), unwrap(b)
+	if len(ua) != len(ub) {
+		return fmt.Sprintf("%s: %d causes != %d", path, len(ua), len(ub))
+	}
+	for i := range ua {
+		next := path + ".Unwrap()"
+		if len(ua) > 1 {
+			next = fmt.Sprintf("%s.Unwrap()[%d]", path, i)
+		}
+		if d := diff(ua[i], ub[i], next); d != "" {
+			return d
+		}
+	}
+	return ""
+}
+
+func unwrap(err error) []error {
+	if members, ok := errorx.JoinedErrors(err); ok {
+		return members
+	}
+	if next := errors.Unwrap(err); next != nil {
+		return []error{next}
+	}
+	return nil
+}
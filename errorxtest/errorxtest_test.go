@@ -0,0 +1,139 @@
+package errorxtest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/ice-coldbell/errorx"
+	_ "github.com/ice-coldbell/errorx/errorxmultierr"
+)
+
+// recorder is a testing.TB that records failures instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func check(t *testing.T, name string, ok bool, r *recorder, wantOK bool, wantMsg string) {
+	t.Helper()
+	if ok != wantOK {
+		t.Errorf("%s = %v, want %v", name, ok, wantOK)
+	}
+	if wantOK != (len(r.errors) == 0) {
+		t.Errorf("%s reported %q", name, r.errors)
+	}
+	if wantMsg != "" && (len(r.errors) == 0 || !strings.Contains(r.errors[0], wantMsg)) {
+		t.Errorf("%s reported %q, want it to contain %q", name, r.errors, wantMsg)
+	}
+}
+
+func newError() error {
+	return errorx.WithSensitive(errorx.WithCode(errorx.New("not found"), "not_found").With("id", 7), "token", "secret")
+}
+
+func TestAssertions(t *testing.T) {
+	err := fmt.Errorf("load: %w", newError())
+	joined := fmt.Errorf("batch: %w", errorx.Join(errors.New("a"), errors.New("b")))
+	hashi := fmt.Errorf("batch: %w", multierror.Append(errors.New("a"), errors.New("b")))
+
+	tests := []struct {
+		name    string
+		assert  func(testing.TB) bool
+		wantOK  bool
+		wantMsg string
+	}{
+		{name: "code", assert: func(t testing.TB) bool { return AssertCode(t, err, "not_found") }, wantOK: true},
+		{name: "wrong code", assert: func(t testing.TB) bool { return AssertCode(t, err, "other") }, wantMsg: `"not_found", want "other"`},
+		{name: "data", assert: func(t testing.TB) bool { return AssertData(t, err, "id", 7) }, wantOK: true},
+		{name: "sensitive data", assert: func(t testing.TB) bool { return AssertData(t, err, "token", "secret") }, wantOK: true},
+		{name: "wrong data", assert: func(t testing.TB) bool { return AssertData(t, err, "id", "7") }, wantMsg: `data "id" = 7, want "7"`},
+		{name: "missing data", assert: func(t testing.TB) bool { return AssertData(t, err, "user", 1) }, wantMsg: `no data "user"`},
+		{name: "stack short name", assert: func(t testing.TB) bool { return AssertStackContains(t, err, "errorxtest.newError") }, wantOK: true},
+		{
+			name: "stack full name",
+			assert: func(t testing.TB) bool {
+				return AssertStackContains(t, err, "github.com/ice-coldbell/errorx/errorxtest.newError")
+			},
+			wantOK: true,
+		},
+		{name: "stack partial name", assert: func(t testing.TB) bool { return AssertStackContains(t, err, "Error") }, wantMsg: "no frame of Error"},
+		{name: "joined", assert: func(t testing.TB) bool { return AssertJoined(t, joined, 2) }, wantOK: true},
+		{name: "joined count", assert: func(t testing.TB) bool { return AssertJoined(t, joined, 3) }, wantMsg: "joins 2 errors, want 3"},
+		{name: "multierror", assert: func(t testing.TB) bool { return AssertJoined(t, hashi, 2) }, wantOK: true},
+		{name: "not joined", assert: func(t testing.TB) bool { return AssertJoined(t, err, 1) }, wantMsg: "not a joined error"},
+		{name: "equal", assert: func(t testing.TB) bool { return AssertEqual(t, err, fmt.Errorf("load: %w", newError())) }, wantOK: true},
+		{name: "not equal", assert: func(t testing.TB) bool { return AssertEqual(t, err, newError()) }, wantMsg: "err: message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			ok := tt.assert(r)
+			check(t, tt.name, ok, r, tt.wantOK, tt.wantMsg)
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	base := func() error {
+		return errorx.Wrap(fmt.Errorf("outer: %w", errorx.WithCode(errorx.New("inner"), "c").With("k", 1)))
+	}
+	tests := []struct {
+		name     string
+		a, b     error
+		want     bool
+		wantDiff string
+	}{
+		{name: "nil", a: nil, b: nil, want: true},
+		{name: "nil and error", a: nil, b: errors.New("x"), wantDiff: "err: <nil> != x"},
+		{name: "same chain, different stacks", a: base(), b: base(), want: true},
+		{
+			name:     "different code",
+			a:        base(),
+			b:        errorx.Wrap(fmt.Errorf("outer: %w", errorx.WithCode(errorx.New("inner"), "d").With("k", 1))),
+			wantDiff: `err: code "c" != "d"`,
+		},
+		{
+			name:     "different data",
+			a:        base(),
+			b:        errorx.Wrap(fmt.Errorf("outer: %w", errorx.WithCode(errorx.New("inner"), "c").With("k", 2))),
+			wantDiff: "err: data map[k:1] != map[k:2]",
+		},
+		{
+			name:     "different depth",
+			a:        base(),
+			b:        errorx.WithCode(errorx.New("outer: inner"), "c").With("k", 1),
+			wantDiff: `err.Unwrap(): code "c" != ""`,
+		},
+		{
+			name: "joined",
+			a:    errorx.Join(errors.New("a"), errorx.WithCode(errorx.New("b"), "x")),
+			b:    errorx.Join(errors.New("a"), errorx.WithCode(errorx.New("b"), "x")),
+			want: true,
+		},
+		{
+			name:     "joined member differs",
+			a:        errorx.Join(errors.New("a"), errorx.New("b").With("k", 1)),
+			b:        errorx.Join(errors.New("a"), errorx.New("b").With("k", 2)),
+			wantDiff: "err: data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+			if d := diff(tt.a, tt.b, "err"); !strings.HasPrefix(d, tt.wantDiff) || (tt.want && d != "") {
+				t.Errorf("diff() = %q, want prefix %q", d, tt.wantDiff)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package errorxtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+// FixedStacks makes the stacks in %+v, JSON and errorx.StackOf output
+// deterministic for the rest of the test, so they can be compared with
+// golden files. It adds errorx.DropRuntime and NormalizeFrames to the
+// current stack filters and restores them when the test ends. Stack
+// filters are global, so tests using it must not run in parallel.
+func FixedStacks(t testing.TB) {
+	t.Helper()
+	prev := errorx.StackFilters()
+	t.Cleanup(func() { errorx.SetStackFilters(prev...) })
+	errorx.SetStackFilters(append(prev, errorx.DropRuntime(), NormalizeFrames)...)
+}
+
+// NormalizeFrames is an errorx.StackFilter that keeps only the function
+// and file name of each frame, dropping the directory, line and program
+// counter, which change with checkouts, edits and builds.
+func NormalizeFrames(s errorx.Stack) errorx.Stack {
+	out := make(errorx.Stack, len(s))
+	for i, f := range s {
+		out[i] = errorx.Frame{
+			Function: f.Function,
+			File:     path.Base(f.File),
+			Package:  f.Package,
+		}
+	}
+	return out
+}
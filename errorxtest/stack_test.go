@@ -0,0 +1,58 @@
+package errorxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func newWrapped() error {
+	return errorx.Wrap(fmt.Errorf("outer: %w", newError()))
+}
+
+func TestFixedStacks(t *testing.T) {
+	t.Run("fixed", func(t *testing.T) {
+		FixedStacks(t)
+		err := newWrapped()
+
+		want := "outer: not found" +
+			"\ngithub.com/ice-coldbell/errorx/errorxtest.newWrapped\n\tstack_test.go:0" +
+			"\ngithub.com/ice-coldbell/errorx/errorxtest.TestFixedStacks.func1\n\tstack_test.go:0" +
+			"\ncaused by: not found\ncode: not_found\ndata: id=7 token=[REDACTED]" +
+			"\ngithub.com/ice-coldbell/errorx/errorxtest.newError\n\terrorxtest_test.go:0" +
+			"\n... 2 frames in common"
+		if got := fmt.Sprintf("%+v", err); got != want {
+			t.Errorf("%%+v = %q, want %q", got, want)
+		}
+
+		b, jerr := json.Marshal(err)
+		if jerr != nil {
+			t.Fatal(jerr)
+		}
+		wantJSON := `{"message":"outer: not found",` +
+			`"stack":["github.com/ice-coldbell/errorx/errorxtest.newWrapped stack_test.go:0",` +
+			`"github.com/ice-coldbell/errorx/errorxtest.TestFixedStacks.func1 stack_test.go:0"],` +
+			`"causes":[{"message":"not found","code":"not_found","data":{"id":7,"token":"[REDACTED]"},` +
+			`"stack":["github.com/ice-coldbell/errorx/errorxtest.newError errorxtest_test.go:0",` +
+			`"github.com/ice-coldbell/errorx/errorxtest.newWrapped stack_test.go:0",` +
+			`"github.com/ice-coldbell/errorx/errorxtest.TestFixedStacks.func1 stack_test.go:0"]}]}`
+		if string(b) != wantJSON {
+			t.Errorf("json = %s, want %s", b, wantJSON)
+		}
+
+		for _, f := range errorx.StackOf(err) {
+			if f.PC != 0 || f.Line != 0 {
+				t.Errorf("StackOf() frame = %+v, want no PC and line", f)
+			}
+		}
+	})
+
+	if got := len(errorx.StackFilters()); got != 0 {
+		t.Errorf("StackFilters() after the test = %d filters, want 0", got)
+	}
+	if st := errorx.StackOf(newWrapped()); len(st) == 0 || st[0].Line == 0 {
+		t.Errorf("StackOf() after the test = %v, want lines", st)
+	}
+}
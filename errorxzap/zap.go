@@ -0,0 +1,76 @@
+package errorxzap
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Error is shorthand for NamedError("error", err).
+func Error(err error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError logs err as an object carrying the same fields as its
+// slog.LogValue, so zap and slog produce the same schema. Errors that are
+// not slog.LogValuer fall back to zap.NamedError.
+func NamedError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	if _, ok := err.(slog.LogValuer); !ok {
+		return zap.NamedError(key, err)
+	}
+	return zap.Object(key, Object(err))
+}
+
+// Object returns a zapcore.ObjectMarshaler for errorx errors.
+func Object(err error) zapcore.ObjectMarshaler {
+	return group(slog.AnyValue(err).Resolve())
+}
+
+type group slog.Value
+
+func (g group) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	v := slog.Value(g)
+	if v.Kind() != slog.KindGroup {
+		enc.AddString("message", v.String())
+		return nil
+	}
+	for _, a := range v.Group() {
+		if err := addAttr(enc, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addAttr(enc zapcore.ObjectEncoder, a slog.Attr) error {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		enc.AddString(a.Key, v.String())
+	case slog.KindInt64:
+		enc.AddInt64(a.Key, v.Int64())
+	case slog.KindUint64:
+		enc.AddUint64(a.Key, v.Uint64())
+	case slog.KindFloat64:
+		enc.AddFloat64(a.Key, v.Float64())
+	case slog.KindBool:
+		enc.AddBool(a.Key, v.Bool())
+	case slog.KindDuration:
+		enc.AddDuration(a.Key, v.Duration())
+	case slog.KindTime:
+		enc.AddTime(a.Key, v.Time())
+	case slog.KindGroup:
+		return enc.AddObject(a.Key, group(v))
+	default:
+		if err, ok := v.Any().(error); ok {
+			enc.AddString(a.Key, err.Error())
+			return nil
+		}
+		return enc.AddReflected(a.Key, v.Any())
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package errorxzap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func slogFields(t *testing.T, err error) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("msg", slog.Any("error", err))
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	return got["error"].(map[string]any)
+}
+
+func zapFields(t *testing.T, field zap.Field) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zap.InfoLevel)
+	zap.New(core).Info("msg", field)
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	return got["error"].(map[string]any)
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "custom error",
+			err:  errorx.New("test").With("user", "alice").With("attempt", 3),
+		},
+		{
+			name: "custom errors",
+			err:  errorx.Join(errorx.New("first").With("id", 1), errors.New("second")),
+		},
+		{
+			name: "sensitive data",
+			err:  errorx.WithSensitive(errorx.New("test"), "token", "secret"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := slogFields(t, tt.err)
+			if got := zapFields(t, Error(tt.err)); !reflect.DeepEqual(got, want) {
+				t.Errorf("Error() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestNamedError(t *testing.T) {
+	if got := NamedError("error", nil); got.Type != zapcore.SkipType {
+		t.Errorf("NamedError(nil) = %v, want skip field", got)
+	}
+	stdErr := errors.New("std")
+	if got := NamedError("error", stdErr); !reflect.DeepEqual(got, zap.NamedError("error", stdErr)) {
+		t.Errorf("NamedError(std) = %v, want zap.NamedError", got)
+	}
+}
@@ -0,0 +1,94 @@
+package errorxzerolog
+
+import (
+	"log/slog"
+	"runtime"
+
+	"github.com/rs/zerolog"
+)
+
+// MarshalError is a zerolog.ErrorMarshalFunc that logs errorx errors with
+// the same fields as their slog.LogValue.
+//
+//	zerolog.ErrorMarshalFunc = errorxzerolog.MarshalError
+func MarshalError(err error) interface{} {
+	if _, ok := err.(slog.LogValuer); !ok {
+		return err
+	}
+	return Object(err)
+}
+
+// MarshalStack is a zerolog.ErrorStackMarshaler for errors exposing
+// StackTrace() []uintptr.
+//
+//	zerolog.ErrorStackMarshaler = errorxzerolog.MarshalStack
+func MarshalStack(err error) interface{} {
+	st, ok := err.(interface{ StackTrace() []uintptr })
+	if !ok {
+		return nil
+	}
+	pcs := st.StackTrace()
+	if len(pcs) == 0 {
+		return nil
+	}
+	var out []map[string]interface{}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		out = append(out, map[string]interface{}{
+			"func":   f.Function,
+			"source": f.File,
+			"line":   f.Line,
+		})
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// Object returns a zerolog.LogObjectMarshaler for errorx errors.
+func Object(err error) zerolog.LogObjectMarshaler {
+	return group(slog.AnyValue(err).Resolve())
+}
+
+type group slog.Value
+
+func (g group) MarshalZerologObject(e *zerolog.Event) {
+	v := slog.Value(g)
+	if v.Kind() != slog.KindGroup {
+		e.Str("message", v.String())
+		return
+	}
+	for _, a := range v.Group() {
+		addAttr(e, a)
+	}
+}
+
+func addAttr(e *zerolog.Event, a slog.Attr) {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		e.Str(a.Key, v.String())
+	case slog.KindInt64:
+		e.Int64(a.Key, v.Int64())
+	case slog.KindUint64:
+		e.Uint64(a.Key, v.Uint64())
+	case slog.KindFloat64:
+		e.Float64(a.Key, v.Float64())
+	case slog.KindBool:
+		e.Bool(a.Key, v.Bool())
+	case slog.KindDuration:
+		e.Dur(a.Key, v.Duration())
+	case slog.KindTime:
+		e.Time(a.Key, v.Time())
+	case slog.KindGroup:
+		e.Object(a.Key, group(v))
+	default:
+		if err, ok := v.Any().(error); ok {
+			e.Str(a.Key, err.Error())
+			return
+		}
+		e.Interface(a.Key, v.Any())
+	}
+}
@@ -0,0 +1,81 @@
+package errorxzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/rs/zerolog"
+)
+
+func slogFields(t *testing.T, err error) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("msg", slog.Any("error", err))
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	return got["error"].(map[string]any)
+}
+
+func TestMarshalError(t *testing.T) {
+	zerolog.ErrorMarshalFunc = MarshalError
+	zerolog.ErrorStackMarshaler = MarshalStack
+	t.Cleanup(func() {
+		zerolog.ErrorMarshalFunc = func(err error) interface{} { return err }
+		zerolog.ErrorStackMarshaler = nil
+	})
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "custom error",
+			err:  errorx.New("test").With("user", "alice").With("attempt", 3),
+		},
+		{
+			name: "custom errors",
+			err:  errorx.Join(errorx.New("first").With("id", 1), errors.New("second")),
+		},
+		{
+			name: "sensitive data",
+			err:  errorx.WithSensitive(errorx.New("test"), "token", "secret"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			logger.Info().Err(tt.err).Msg("msg")
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			want := slogFields(t, tt.err)
+			if !reflect.DeepEqual(got["error"], want) {
+				t.Errorf("MarshalError() = %v, want %v", got["error"], want)
+			}
+		})
+	}
+}
+
+func TestMarshalStack(t *testing.T) {
+	if got := MarshalStack(errors.New("std")); got != nil {
+		t.Errorf("MarshalStack(std) = %v, want nil", got)
+	}
+	frames, ok := MarshalStack(errorx.New("test")).([]map[string]interface{})
+	if !ok || len(frames) == 0 {
+		t.Fatalf("MarshalStack() = %v, want frames", frames)
+	}
+	for _, key := range []string{"func", "source", "line"} {
+		if _, ok := frames[0][key]; !ok {
+			t.Errorf("MarshalStack() frame = %v, missing %q", frames[0], key)
+		}
+	}
+}
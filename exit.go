@@ -0,0 +1,124 @@
+package errorx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// DefaultExitCode is the exit code of errors no other rule applies to.
+var DefaultExitCode = 1
+
+// ExitCodeCanceled is the exit code of context.Canceled, the code shells
+// use for a process interrupted with Ctrl-C.
+const ExitCodeCanceled = 130
+
+var exitCodes = struct {
+	sync.RWMutex
+	byCode   map[string]int
+	byTarget []exitTarget
+}{byCode: make(map[string]int)}
+
+type exitTarget struct {
+	target   error
+	exitCode int
+}
+
+// RegisterExitCode sets the exit code of errors with the given code. An
+// exit code of 0 is skipped, as with WithExitCode.
+func RegisterExitCode(code string, exitCode int) {
+	exitCodes.Lock()
+	defer exitCodes.Unlock()
+	exitCodes.byCode[code] = exitCode
+}
+
+// RegisterExitCodeFor sets the exit code of errors matching target with
+// errors.Is. Targets are tried in registration order and an exit code of 0
+// is skipped.
+func RegisterExitCodeFor(target error, exitCode int) {
+	exitCodes.Lock()
+	defer exitCodes.Unlock()
+	exitCodes.byTarget = append(exitCodes.byTarget, exitTarget{target, exitCode})
+}
+
+// WithExitCode sets the process exit code used by Exit and ExitCode. A
+// code of 0 counts as unset, so a non-nil error never exits successfully.
+func WithExitCode(err error, code int) CustomError {
+	return custom(err).withExitCode(code)
+}
+
+// ExitCode returns the process exit code for err. It is 0 for nil and
+// otherwise, in order of precedence:
+//   - the outermost non-zero code set with WithExitCode
+//   - the non-zero exit code registered for the error's code
+//   - the non-zero exit code registered for the first matching target
+//   - the exit code of an *exec.ExitError, so failed child commands
+//     propagate their status
+//   - ExitCodeCanceled for context.Canceled
+//   - DefaultExitCode
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var explicit *int
+	walkCustom(err, func(e *customError) bool {
+		if e.exitCode != nil && *e.exitCode != 0 {
+			explicit = e.exitCode
+		}
+		return explicit == nil
+	})
+	if explicit != nil {
+		return *explicit
+	}
+
+	if exitCode, ok := registeredExitCode(err); ok {
+		return exitCode
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitCodeCanceled
+	}
+	return DefaultExitCode
+}
+
+func registeredExitCode(err error) (int, bool) {
+	exitCodes.RLock()
+	defer exitCodes.RUnlock()
+	if code := Code(err); code != "" {
+		if exitCode := exitCodes.byCode[code]; exitCode != 0 {
+			return exitCode, true
+		}
+	}
+	for _, t := range exitCodes.byTarget {
+		if t.exitCode != 0 && errors.Is(err, t.target) {
+			return t.exitCode, true
+		}
+	}
+	return 0, false
+}
+
+// for test injection
+var (
+	osExit             = os.Exit
+	osStderr io.Writer = os.Stderr
+)
+
+// Exit prints err to stderr with RenderTerminal and exits the process with
+// ExitCode(err). A nil err exits with 0 without printing.
+//
+//	func main() {
+//		errorx.Exit(run(context.Background()))
+//	}
+func Exit(err error) {
+	if err != nil {
+		RenderTerminal(osStderr, err, TerminalOptions{})
+	}
+	osExit(ExitCode(err))
+}
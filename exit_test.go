@@ -0,0 +1,83 @@
+package errorx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Cleanup(func() {
+		exitCodes.byCode = make(map[string]int)
+		exitCodes.byTarget = nil
+	})
+	errUsage := errors.New("usage")
+	RegisterExitCode("not_found", 4)
+	RegisterExitCodeFor(errUsage, 2)
+	RegisterExitCode("ok", 0)
+	RegisterExitCodeFor(os.ErrPermission, 0)
+	RegisterExitCodeFor(os.ErrPermission, 77)
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	childErr := exec.Command(sh, "-c", "exit 3").Run()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: 0},
+		{name: "default", err: errors.New("boom"), want: DefaultExitCode},
+		{name: "explicit", err: WithExitCode(New("boom"), 9), want: 9},
+		{name: "explicit zero", err: WithExitCode(New("boom"), 0), want: DefaultExitCode},
+		{name: "explicit zero over inner", err: WithExitCode(Wrap(fmt.Errorf("x: %w", WithExitCode(New("boom"), 9))), 0), want: 9},
+		{name: "outermost explicit", err: WithExitCode(Wrap(fmt.Errorf("x: %w", WithExitCode(New("boom"), 9))), 8), want: 8},
+		{name: "explicit over code", err: WithExitCode(WithCode(New("boom"), "not_found"), 5), want: 5},
+		{name: "code", err: WithCode(New("boom"), "not_found"), want: 4},
+		{name: "registered zero", err: WithCode(New("boom"), "ok"), want: DefaultExitCode},
+		{name: "target", err: fmt.Errorf("parse flags: %w", errUsage), want: 2},
+		{name: "wrapped target", err: Wrap(&os.PathError{Op: "open", Path: "x", Err: os.ErrPermission}), want: 77},
+		{name: "exec", err: Wrap(childErr), want: 3},
+		{name: "canceled", err: fmt.Errorf("run: %w", context.Canceled), want: ExitCodeCanceled},
+		{name: "joined", err: Join(errors.New("a"), errUsage), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExit(t *testing.T) {
+	t.Cleanup(func() {
+		osExit = os.Exit
+		osStderr = os.Stderr
+	})
+	var code int
+	var stderr strings.Builder
+	osExit = func(c int) { code = c }
+	osStderr = &stderr
+
+	Exit(WithHint(WithExitCode(New("boom"), 3), "try again"))
+	if code != 3 {
+		t.Errorf("Exit() code = %d, want 3", code)
+	}
+	if want := "error: boom\n  hint: try again\n"; stderr.String() != want {
+		t.Errorf("Exit() stderr = %q, want %q", stderr.String(), want)
+	}
+
+	stderr.Reset()
+	Exit(nil)
+	if code != 0 || stderr.Len() != 0 {
+		t.Errorf("Exit(nil) code = %d, stderr = %q", code, stderr.String())
+	}
+}
@@ -0,0 +1,92 @@
+package errorx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FingerprintFrames is the number of innermost stack frames that take part
+// in a fingerprint.
+var FingerprintFrames = 5
+
+var messageNormalizers = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), `"?"`},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "?"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "?"},
+	{regexp.MustCompile(`\d+`), "?"},
+}
+
+// normalizeMessage replaces quoted strings, UUIDs, hex and decimal numbers
+// so that messages differing only in their parameters compare equal.
+func normalizeMessage(message string) string {
+	for _, n := range messageNormalizers {
+		message = n.re.ReplaceAllString(message, n.repl)
+	}
+	return message
+}
+
+// WithFingerprint overrides the fingerprint computed by Fingerprint.
+func WithFingerprint(err error, fingerprint string) CustomError {
+	return custom(err).withFingerprint(fingerprint)
+}
+
+// Fingerprint returns a stable hash for grouping occurrences of the same
+// error. It covers the error code, the type and normalized message of the
+// root cause and the function names of the top FingerprintFrames frames of
+// the deepest captured stack, so it does not change with line numbers,
+// addresses or parameters in the message. Joined errors combine the
+// fingerprints of their members. WithFingerprint overrides the result.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	h := sha256.New()
+	var (
+		code  string
+		st    stack
+		cause error
+	)
+	for cur := err; cur != nil; {
+		cause = cur
+		if e, ok := cur.(*customError); ok && e != nil {
+			if e.fingerprint != "" {
+				return e.fingerprint
+			}
+			if code == "" {
+				code = e.code
+			}
+			if len(e.stack) > 0 {
+				st = e.stack
+			}
+		}
+		if members, ok := JoinedErrors(cur); ok {
+			for _, member := range members {
+				fmt.Fprintf(h, "member:%s\n", Fingerprint(member))
+			}
+			cause = nil
+			break
+		}
+		cur = errors.Unwrap(cur)
+	}
+
+	fmt.Fprintf(h, "code:%s\n", code)
+	if cause != nil {
+		fmt.Fprintf(h, "type:%T\n", cause)
+		fmt.Fprintf(h, "message:%s\n", normalizeMessage(strings.TrimSpace(cause.Error())))
+	}
+	for i, f := range st {
+		if i >= FingerprintFrames {
+			break
+		}
+		fmt.Fprintf(h, "frame:%s\n", f.function)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
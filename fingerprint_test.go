@@ -0,0 +1,77 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func Test_normalizeMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{message: "user 42 not found", want: "user ? not found"},
+		{message: `open "/tmp/a.txt": no such file`, want: `open "?": no such file`},
+		{message: "bad pointer 0xc000012345", want: "bad pointer ?"},
+		{message: "order 3f2504e0-4f89-11d3-9a0c-0305e82c3301 missing", want: "order ? missing"},
+		{message: "plain message", want: "plain message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := normalizeMessage(tt.message); got != tt.want {
+				t.Errorf("normalizeMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newFingerprintTestError(id int) CustomError {
+	return WithCode(New(fmt.Sprintf("user %d not found", id)), "not_found")
+}
+
+func otherFingerprintTestError(id int) CustomError {
+	return WithCode(New(fmt.Sprintf("user %d not found", id)), "not_found")
+}
+
+func TestFingerprint(t *testing.T) {
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+
+	same1 := newFingerprintTestError(1)
+	same2 := newFingerprintTestError(2)
+
+	tests := []struct {
+		name  string
+		a, b  error
+		equal bool
+	}{
+		{name: "different parameters", a: same1, b: same2, equal: true},
+		{name: "wrapped with fmt", a: same1, b: fmt.Errorf("handler: %w", same2), equal: true},
+		{name: "different call site", a: same1, b: otherFingerprintTestError(1), equal: false},
+		{name: "different code", a: same1, b: WithCode(newFingerprintTestError(1), "gone"), equal: false},
+		{name: "different type", a: Wrap(errors.New("timeout")), b: Wrap(timeoutError{}), equal: false},
+		{name: "joined", a: Join(same1, same2), b: Join(same2, same1), equal: true},
+		{name: "joined members differ", a: Join(same1), b: Join(same1, same2), equal: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := Fingerprint(tt.a), Fingerprint(tt.b)
+			if (a == b) != tt.equal {
+				t.Errorf("Fingerprint() = %v and %v, want equal %v", a, b, tt.equal)
+			}
+		})
+	}
+
+	if got := Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil) = %v, want empty", got)
+	}
+	if got := Fingerprint(fmt.Errorf("x: %w", WithFingerprint(New("x"), "custom"))); got != "custom" {
+		t.Errorf("Fingerprint() = %v, want custom", got)
+	}
+	var nilErr *customError
+	if got := WithFingerprint(nilErr, "custom"); got != nil {
+		t.Errorf("WithFingerprint(nil) = %v, want nil", got)
+	}
+}
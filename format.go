@@ -0,0 +1,137 @@
+package errorx
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Format supports the following verbs:
+//
+//	%s, %v  the error message
+//	%q      the quoted error message
+//	%+v     the message, code, data and stack, followed by every
+//	        CustomError or CustomErrors in the wrap chain. Frames an inner
+//	        stack shares with the stack printed before it are replaced by
+//	        a "... N frames in common" line.
+func (e *customError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.formatVerbose(s, nil, nil)
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// formatVerbose writes the %+v form of e. prev is the stack printed before
+// it, whose common suffix is elided from e's stack. Render passes opts to
+// add source snippets.
+func (e *customError) formatVerbose(w io.Writer, prev stack, opts *RenderOptions) {
+	io.WriteString(w, e.Error())
+	if e == nil {
+		return
+	}
+	if e.code != "" {
+		fmt.Fprintf(w, "\ncode: %s", e.code)
+	}
+	if len(e.data) > 0 {
+		keys := make([]string, 0, len(e.data))
+		for k := range e.data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		io.WriteString(w, "\ndata:")
+		for _, k := range keys {
+			fmt.Fprintf(w, " %s=%v", k, e.data[k])
+		}
+	}
+	st := e.stack.filtered()
+	writeStack(w, st, prev, opts)
+	if len(st) == 0 {
+		st = prev
+	}
+	if cause := nextCustom(e.err); cause != nil {
+		io.WriteString(w, "\ncaused by: ")
+		formatVerbose(w, cause, st, opts)
+	}
+}
+
+func formatVerbose(w io.Writer, err error, prev stack, opts *RenderOptions) {
+	switch err := err.(type) {
+	case *customError:
+		err.formatVerbose(w, prev, opts)
+	case customErrors:
+		err.formatVerbose(w, prev, opts)
+	default:
+		fmt.Fprintf(w, "%+v", err)
+	}
+}
+
+// writeStack writes st like its %+v form, but replaces the frames it has in
+// common with the end of prev by a marker line.
+func writeStack(w io.Writer, st, prev stack, opts *RenderOptions) {
+	common := commonSuffix(st, prev)
+	for _, f := range st[:len(st)-common] {
+		fmt.Fprintf(w, "\n%+v", f)
+		if opts != nil {
+			opts.writeSnippet(w, f)
+		}
+	}
+	if common > 0 {
+		io.WriteString(w, "\n"+inCommon(common))
+	}
+}
+
+// Format supports the same verbs as CustomError. With %+v every joined
+// error is printed in full, eliding the frames each member's stack shares
+// with the member before it.
+func (e customErrors) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.formatVerbose(s, nil, nil)
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// commonSuffix returns the number of outermost frames st shares with prev.
+func commonSuffix(st, prev stack) int {
+	n := 0
+	for n < len(st) && n < len(prev) && st[len(st)-1-n] == prev[len(prev)-1-n] {
+		n++
+	}
+	return n
+}
+
+// inCommon returns the marker line for n elided frames.
+func inCommon(n int) string {
+	if n == 1 {
+		return "... 1 frame in common"
+	}
+	return fmt.Sprintf("... %d frames in common", n)
+}
+
+func (e customErrors) formatVerbose(w io.Writer, prev stack, opts *RenderOptions) {
+	for i, err := range e {
+		if i > 0 {
+			io.WriteString(w, "\n")
+		}
+		fmt.Fprintf(w, "[%d] ", i)
+		formatVerbose(w, err, prev, opts)
+		if ce, ok := err.(*customError); ok && len(ce.stack) > 0 {
+			prev = ce.stack.filtered()
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_customError_Format(t *testing.T) {
+	runtimeCallers = func(skip int, pc []uintptr) int {
+		pc[0] = globalTestPC
+		return 1
+	}
+	stackText := fmt.Sprintf("%+v", stack{globalTestFrame})
+
+	inner := WithCode(New("inner"), "inner_code")
+	tests := []struct {
+		name   string
+		err    error
+		format string
+		want   string
+	}{
+		{name: "verb s", err: New("test"), format: "%s", want: "test"},
+		{name: "verb v", err: New("test"), format: "%v", want: "test"},
+		{name: "verb q", err: New("test"), format: "%q", want: `"test"`},
+		{
+			name:   "verb +v",
+			err:    WithSensitive(WithCode(New("test"), "code").With("b", 2).With("a", "x"), "token", "secret"),
+			format: "%+v",
+			want:   "test\ncode: code\ndata: a=x b=2 token=[REDACTED]" + stackText,
+		},
+		{
+			name:   "verb +v with cause",
+			err:    Wrap(fmt.Errorf("outer: %w", inner)),
+			format: "%+v",
+			want:   "outer: inner" + stackText + "\ncaused by: inner\ncode: inner_code\n... 1 frame in common",
+		},
+		{
+			name:   "joined verb v",
+			err:    Join(inner, errors.New("std")),
+			format: "%v",
+			want:   "inner\nstd",
+		},
+		{
+			name:   "joined verb +v",
+			err:    Join(inner, errors.New("std")),
+			format: "%+v",
+			want:   "[0] inner\ncode: inner_code" + stackText + "\n[1] std\n... 1 frame in common",
+		},
+		{
+			name:   "joined verb q",
+			err:    Join(inner, errors.New("std")),
+			format: "%q",
+			want:   `"inner\nstd"`,
+		},
+		{
+			name:   "nil error",
+			err:    (*customError)(nil),
+			format: "%+v",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+				t.Errorf("fmt.Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeStack(t *testing.T) {
+	f := func(function string, line int) frame {
+		return frame{function: function, file: "f.go", line: line}
+	}
+	main, run := f("main.main", 1), f("main.run", 2)
+	tests := []struct {
+		name string
+		st   stack
+		prev stack
+		want string
+	}{
+		{
+			name: "no previous stack",
+			st:   stack{run, main},
+			want: fmt.Sprintf("%+v", stack{run, main}),
+		},
+		{
+			name: "common suffix",
+			st:   stack{f("main.inner", 3), f("main.run", 4), main},
+			prev: stack{run, main},
+			want: fmt.Sprintf("%+v", stack{f("main.inner", 3), f("main.run", 4)}) + "\n... 1 frame in common",
+		},
+		{
+			name: "same stack",
+			st:   stack{run, main},
+			prev: stack{run, main},
+			want: "\n... 2 frames in common",
+		},
+		{
+			name: "nothing in common",
+			st:   stack{run},
+			prev: stack{main},
+			want: fmt.Sprintf("%+v", stack{run}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeStack(&b, tt.st, tt.prev, nil)
+			if got := b.String(); got != tt.want {
+				t.Errorf("writeStack() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package errorx
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Frame is a resolved stack frame. PC is zero for frames decoded from JSON
+// or parsed from panic text.
+type Frame struct {
+	PC       uintptr
+	Function string
+	File     string
+	Line     int
+	// Package is the import path of the package declaring Function.
+	Package string
+	// Inlined reports whether Function was inlined into its caller.
+	Inlined bool
+}
+
+// Stack is a captured stack, innermost frame first.
+type Stack []Frame
+
+// StackOf returns the deepest stack captured in err's wrap chain, which is
+// the one closest to where the error originated, after the filters set
+// with SetStackFilters.
+func StackOf(err error) Stack {
+	st := deepestStack(err).filtered()
+	if len(st) == 0 {
+		return nil
+	}
+	out := make(Stack, len(st))
+	for i, f := range st {
+		out[i] = f.export()
+	}
+	return out
+}
+
+// StackPCs returns the program counters of the deepest stack captured in
+// err's wrap chain, one per logical frame, before the filters set with
+// SetStackFilters are applied.
+func StackPCs(err error) []uintptr {
+	st := deepestStack(err)
+	if len(st) == 0 {
+		return nil
+	}
+	return st.StackTrace()
+}
+
+func (f frame) export() Frame {
+	return Frame{
+		PC:       f.pc,
+		Function: f.function,
+		File:     f.file,
+		Line:     f.line,
+		Package:  packageName(f.function),
+		Inlined:  inlined(f.pc),
+	}
+}
+
+func (f Frame) internal() frame {
+	return frame{pc: f.PC, file: f.File, function: f.Function, line: f.Line}
+}
+
+// inlinedPCs caches inlined, which every filtered or exported stack calls
+// for each of its frames.
+var inlinedPCs sync.Map // uintptr -> bool
+
+// inlined reports whether the logical frame recorded at pc by
+// runtime.Callers belongs to an inlined call.
+func inlined(pc uintptr) bool {
+	if pc == 0 {
+		return false
+	}
+	if v, ok := inlinedPCs.Load(pc); ok {
+		return v.(bool)
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	in := f.Func == nil && f.Function != ""
+	inlinedPCs.Store(pc, in)
+	return in
+}
+
+// packageName returns the import path part of a fully qualified function
+// name such as "github.com/a/b.(*T).Method". The linker escapes dots in the
+// last path element as %2e, so "gopkg.in/yaml%2ev3.Marshal" is "gopkg.in/yaml.v3".
+func packageName(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	if dot := strings.IndexByte(function[slash+1:], '.'); dot >= 0 {
+		return strings.ReplaceAll(function[:slash+1+dot], "%2e", ".")
+	}
+	return ""
+}
+
+// Format formats the stack like the stack printed by CustomError's %+v.
+func (s Stack) Format(st fmt.State, verb rune) {
+	in := make(stack, len(s))
+	for i, f := range s {
+		in[i] = f.internal()
+	}
+	in.Format(st, verb)
+}
+
+// Frames returns an iterator over the stack.
+//
+//	frames := st.Frames()
+//	for f, ok := frames.Next(); ok; f, ok = frames.Next() {
+//		...
+//	}
+func (s Stack) Frames() *Frames {
+	return &Frames{stack: s}
+}
+
+type Frames struct {
+	stack Stack
+	next  int
+}
+
+// Next returns the next frame and true, or a zero Frame and false once the
+// stack is exhausted.
+func (f *Frames) Next() (Frame, bool) {
+	if f.next >= len(f.stack) {
+		return Frame{}, false
+	}
+	f.next++
+	return f.stack[f.next-1], true
+}
@@ -0,0 +1,101 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func Test_packageName(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{function: "main.main", want: "main"},
+		{function: "runtime.goexit", want: "runtime"},
+		{function: "github.com/ice-coldbell/errorx.New", want: "github.com/ice-coldbell/errorx"},
+		{function: "github.com/a/b.(*T).Method.func1", want: "github.com/a/b"},
+		{function: "gopkg.in/yaml%2ev3.Unmarshal", want: "gopkg.in/yaml.v3"},
+		{function: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := packageName(tt.function); got != tt.want {
+			t.Errorf("packageName(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
+
+func inlinedCallers() stack {
+	return callers(2)
+}
+
+func Test_inlined(t *testing.T) {
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+
+	st := inlinedCallers()
+	if len(st) < 2 {
+		t.Fatalf("callers() = %v, want at least 2 frames", st)
+	}
+	if !inlined(st[0].pc) {
+		t.Errorf("inlined(%v) = false, want true", st[0])
+	}
+	if inlined(st[1].pc) {
+		t.Errorf("inlined(%v) = true, want false", st[1])
+	}
+	if inlined(0) {
+		t.Error("inlined(0) = true, want false")
+	}
+}
+
+func TestStackOf(t *testing.T) {
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+
+	if got := StackOf(errors.New("std")); got != nil {
+		t.Errorf("StackOf(std) = %v, want nil", got)
+	}
+
+	inner := New("inner")
+	err := Wrap(fmt.Errorf("outer: %w", inner))
+	got := StackOf(err)
+	if len(got) < 2 {
+		t.Fatalf("StackOf() = %v, want inner stack", got)
+	}
+	want := inner.(*customError).stack
+	for i, f := range got {
+		if f.PC != want[i].pc || f.Function != want[i].function || f.File != want[i].file || f.Line != want[i].line {
+			t.Errorf("StackOf()[%d] = %+v, want %#v", i, f, want[i])
+		}
+	}
+	var found bool
+	for _, f := range got {
+		if f.Function == "github.com/ice-coldbell/errorx.TestStackOf" {
+			found = f.Package == "github.com/ice-coldbell/errorx"
+		}
+	}
+	if !found {
+		t.Errorf("StackOf() = %v, want TestStackOf frame in package errorx", got)
+	}
+
+	if got, want := fmt.Sprintf("%+v", got), fmt.Sprintf("%+v", want); got != want {
+		t.Errorf("Stack %%+v = %q, want %q", got, want)
+	}
+}
+
+func TestStack_Frames(t *testing.T) {
+	st := Stack{{Function: "a"}, {Function: "b"}}
+	var got Stack
+	frames := st.Frames()
+	for f, ok := frames.Next(); ok; f, ok = frames.Next() {
+		got = append(got, f)
+	}
+	if !reflect.DeepEqual(got, st) {
+		t.Errorf("Stack.Frames() = %v, want %v", got, st)
+	}
+	if _, ok := Stack(nil).Frames().Next(); ok {
+		t.Error("Stack(nil).Frames().Next() = true, want false")
+	}
+}
@@ -2,10 +2,25 @@ module github.com/ice-coldbell/errorx
 
 go 1.21.5
 
-require github.com/stretchr/testify v1.8.4
+require (
+	github.com/hashicorp/go-multierror v1.1.1
+	github.com/pkg/errors v0.9.1
+	github.com/rs/zerolog v1.33.0
+	github.com/stretchr/testify v1.8.4
+	go.uber.org/multierr v1.10.0
+	go.uber.org/zap v1.27.0
+	golang.org/x/text v0.14.0
+	golang.org/x/tools v0.24.1
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/hashicorp/errwrap v1.1.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/mod v0.20.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.23.0 // indirect
 )
@@ -0,0 +1,19 @@
+package errorx
+
+// WithHint adds a suggestion for the user on how to resolve err.
+func WithHint(err error, hint string) CustomError {
+	return custom(err).withHint(hint)
+}
+
+// Hints returns the hints set with WithHint in err's chain, outermost
+// first.
+func Hints(err error) []string {
+	var hints []string
+	walkCustom(err, func(e *customError) bool {
+		if e.hint != "" {
+			hints = append(hints, e.hint)
+		}
+		return true
+	})
+	return hints
+}
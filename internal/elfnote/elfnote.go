@@ -0,0 +1,35 @@
+// Package elfnote reads the Go build ID note of ELF binaries.
+package elfnote
+
+import (
+	"bytes"
+	"debug/elf"
+	"errors"
+)
+
+// BuildID returns the Go build ID stored in f's .note.go.buildid section.
+func BuildID(f *elf.File) (string, error) {
+	s := f.Section(".note.go.buildid")
+	if s == nil {
+		return "", errors.New("elfnote: missing .note.go.buildid section")
+	}
+	data, err := s.Data()
+	if err != nil {
+		return "", err
+	}
+	// Note layout: namesz, descsz, type, name padded to 4 bytes, desc.
+	if len(data) < 12 {
+		return "", errors.New("elfnote: short build ID note")
+	}
+	namesz := f.ByteOrder.Uint32(data[0:])
+	descsz := f.ByteOrder.Uint32(data[4:])
+	name := data[12:]
+	if uint32(len(name)) < namesz || !bytes.Equal(bytes.TrimRight(name[:namesz], "\x00"), []byte("Go")) {
+		return "", errors.New("elfnote: not a Go build ID note")
+	}
+	desc := name[(namesz+3)&^3:]
+	if uint32(len(desc)) < descsz {
+		return "", errors.New("elfnote: short build ID note")
+	}
+	return string(desc[:descsz]), nil
+}
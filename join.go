@@ -1,8 +1,13 @@
 package errorx
 
+// Join returns the non-nil errs as joined errors. Joined errors of this
+// package and multi-errors registered with RegisterMultiError are flattened
+// into their members. Members without a stack get the stack of the caller.
 func Join(errs ...error) customErrors {
 	var cErrs customErrors
-	for _, err := range errs {
+	for len(errs) > 0 {
+		err := errs[0]
+		errs = errs[1:]
 		switch tErr := err.(type) {
 		case nil:
 			continue
@@ -11,6 +16,10 @@ func Join(errs ...error) customErrors {
 		case *customError:
 			cErrs = append(cErrs, tErr)
 		default:
+			if members, ok := multiErrors(err); ok {
+				errs = append(members[:len(members):len(members)], errs...)
+				continue
+			}
 			cErrs = append(cErrs, WrapDepth(err, 4))
 		}
 	}
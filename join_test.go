@@ -2,6 +2,7 @@ package errorx
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -93,3 +94,45 @@ func TestJoinErrorMethod(t *testing.T) {
 		}
 	}
 }
+
+// batchError has the Errors() []error method of multierr but a message of
+// its own, so it is not flattened unless registered.
+type batchError struct {
+	errs []error
+}
+
+func (e *batchError) Error() string { return fmt.Sprintf("batch of %d", len(e.errs)) }
+
+func (e *batchError) Errors() []error { return e.errs }
+
+type registeredMulti []error
+
+func (e registeredMulti) Error() string { return "registered" }
+
+func TestRegisterMultiError(t *testing.T) {
+	RegisterMultiError(func(err error) ([]error, bool) {
+		if e, ok := err.(registeredMulti); ok {
+			return e, true
+		}
+		return nil, false
+	})
+
+	batch := &batchError{errs: []error{errors.New("a"), errors.New("b")}}
+	if got := Join(batch); len(got) != 1 || got.Error() != "batch of 2" {
+		t.Errorf("Join(unregistered) = %q, want the error kept whole", got.Error())
+	}
+	if _, ok := JoinedErrors(batch); ok {
+		t.Error("JoinedErrors(unregistered) = true, want false")
+	}
+
+	multi := registeredMulti{errors.New("a"), WithCode(New("b"), "b_code")}
+	if got := Join(multi, errors.New("c")); len(got) != 3 || got.Error() != "a\nb\nc" {
+		t.Errorf("Join(registered) = %q, want 3 members", got.Error())
+	}
+	if got := Code(fmt.Errorf("wrap: %w", multi)); got != "b_code" {
+		t.Errorf("Code() = %q, want %q", got, "b_code")
+	}
+	if members, ok := JoinedErrors(errors.Join(errors.New("a"), errors.New("b"))); !ok || len(members) != 2 {
+		t.Errorf("JoinedErrors(errors.Join()) = %v, %v", members, ok)
+	}
+}
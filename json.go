@@ -0,0 +1,152 @@
+package errorx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type jsonError struct {
+	Message string                     `json:"message"`
+	Code    string                     `json:"code,omitempty"`
+	Data    map[string]json.RawMessage `json:"data,omitempty"`
+	Stack   stack                      `json:"stack,omitempty"`
+	Causes  []error                    `json:"causes,omitempty"`
+}
+
+func (e *customError) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	v := jsonError{
+		Message: e.Error(),
+		Code:    e.code,
+		Data:    marshalData(e.data),
+		Stack:   e.stack,
+	}
+	if cause := nextCustom(e.err); cause != nil {
+		v.Causes = []error{cause}
+	}
+	return json.Marshal(v)
+}
+
+// marshalData encodes each value on its own, so a value JSON cannot encode,
+// such as a func or channel, is written as fmt.Sprint(v) instead of failing
+// the whole error.
+func marshalData(data map[string]any) map[string]json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	out := make(map[string]json.RawMessage, len(data))
+	for k, v := range data {
+		b, err := json.Marshal(v)
+		if err != nil {
+			b, _ = json.Marshal(fmt.Sprint(v))
+		}
+		out[k] = b
+	}
+	return out
+}
+
+func (e customErrors) MarshalJSON() ([]byte, error) {
+	v := jsonError{Message: e.Error()}
+	for _, err := range e {
+		v.Causes = append(v.Causes, err)
+	}
+	return json.Marshal(v)
+}
+
+// nextCustom returns the first error in err's wrap chain that is a
+// CustomError or CustomErrors.
+func nextCustom(err error) error {
+	var found error
+	walk(err, func(err error) bool {
+		switch err.(type) {
+		case *customError, customErrors:
+			found = err
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// FromJSON decodes an error encoded with json.Marshal from a CustomError or
+// CustomErrors. Stack frames keep their function, file and line but not
+// their program counter. Causes are reachable through Unwrap.
+func FromJSON(b []byte) (CustomError, error) {
+	e := &customError{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *customError) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Message string         `json:"message"`
+		Code    string         `json:"code"`
+		Data    map[string]any `json:"data"`
+		Stack   stack          `json:"stack"`
+		Causes  []*customError `json:"causes"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	decoded := &decodedError{message: v.Message}
+	switch len(v.Causes) {
+	case 0:
+	case 1:
+		decoded.cause = v.Causes[0]
+	default:
+		causes := make(customErrors, len(v.Causes))
+		for i := range v.Causes {
+			causes[i] = v.Causes[i]
+		}
+		decoded.cause = causes
+	}
+	if v.Data == nil {
+		v.Data = make(map[string]any)
+	}
+	*e = customError{
+		err:   decoded,
+		stack: v.Stack,
+		data:  v.Data,
+		code:  v.Code,
+	}
+	return nil
+}
+
+// decodedError stands in for the original error of a decoded CustomError.
+type decodedError struct {
+	message string
+	cause   error
+}
+
+func (e *decodedError) Error() string {
+	return e.message
+}
+
+func (e *decodedError) Unwrap() error {
+	return e.cause
+}
+
+func (f *frame) UnmarshalText(text []byte) error {
+	s := string(text)
+	function, location, ok := strings.Cut(s, " ")
+	if !ok {
+		return fmt.Errorf("errorx: invalid frame %q", s)
+	}
+	i := strings.LastIndexByte(location, ':')
+	if i < 0 {
+		return fmt.Errorf("errorx: invalid frame %q", s)
+	}
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return fmt.Errorf("errorx: invalid frame %q", s)
+	}
+	*f = frame{function: function, file: location[:i], line: line}
+	return nil
+}
@@ -0,0 +1,142 @@
+package errorx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_customError_MarshalJSON(t *testing.T) {
+	runtimeCallers = func(skip int, pc []uintptr) int {
+		pc[0] = globalTestPC
+		return 1
+	}
+	frameText, _ := globalTestFrame.MarshalText()
+
+	inner := WithCode(New("inner"), "inner_code")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "common case",
+			err:  WithSensitive(WithCode(New("test"), "code").With("key", "value"), "token", "secret"),
+			want: fmt.Sprintf(`{"message":"test","code":"code","data":{"key":"value","token":"[REDACTED]"},"stack":[%q]}`, frameText),
+		},
+		{
+			name: "wrapped cause",
+			err:  Wrap(fmt.Errorf("outer: %w", inner)),
+			want: fmt.Sprintf(`{"message":"outer: inner","stack":[%q],"causes":[{"message":"inner","code":"inner_code","stack":[%q]}]}`, frameText, frameText),
+		},
+		{
+			name: "joined errors",
+			err:  Join(inner, errors.New("std")),
+			want: fmt.Sprintf(`{"message":"inner\nstd","causes":[{"message":"inner","code":"inner_code","stack":[%q]},{"message":"std","stack":[%q]}]}`, frameText, frameText),
+		},
+		{
+			name: "unencodable data",
+			err:  New("test").With("ratio", math.Inf(1)).With("count", 1),
+			want: fmt.Sprintf(`{"message":"test","data":{"count":1,"ratio":"+Inf"},"stack":[%q]}`, frameText),
+		},
+		{
+			name: "nil error",
+			err:  (*customError)(nil),
+			want: "null",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	runtimeCallers = func(skip int, pc []uintptr) int {
+		pc[0] = globalTestPC
+		return 1
+	}
+	withoutPC := globalTestFrame
+	withoutPC.pc = 0
+
+	inner := WithCode(New("inner"), "inner_code").With("id", 1)
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantData   map[string]any
+		wantCauses []string
+	}{
+		{
+			name:     "single error",
+			err:      inner,
+			wantCode: "inner_code",
+			wantData: map[string]any{"id": float64(1)},
+		},
+		{
+			name:       "wrapped cause",
+			err:        Wrap(fmt.Errorf("outer: %w", inner)).With("outer", true),
+			wantCode:   "inner_code",
+			wantData:   map[string]any{"id": float64(1), "outer": true},
+			wantCauses: []string{"inner"},
+		},
+		{
+			name:       "joined errors",
+			err:        Join(inner, errors.New("std")),
+			wantCode:   "inner_code",
+			wantData:   map[string]any{"id": float64(1)},
+			wantCauses: []string{"inner", "std"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := FromJSON(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Error() != tt.err.Error() {
+				t.Errorf("FromJSON().Error() = %q, want %q", got.Error(), tt.err.Error())
+			}
+			if code := Code(got); code != tt.wantCode {
+				t.Errorf("Code(FromJSON()) = %v, want %v", code, tt.wantCode)
+			}
+			if data := Data(got); !reflect.DeepEqual(data, tt.wantData) {
+				t.Errorf("Data(FromJSON()) = %v, want %v", data, tt.wantData)
+			}
+			var causes []string
+			walkCustom(got, func(e *customError) bool {
+				if e != got {
+					causes = append(causes, e.Error())
+				}
+				return true
+			})
+			if !reflect.DeepEqual(causes, tt.wantCauses) {
+				t.Errorf("FromJSON() causes = %q, want %q", causes, tt.wantCauses)
+			}
+			if st := got.(*customError).stack; len(st) > 0 && !reflect.DeepEqual(st, stack{withoutPC}) {
+				t.Errorf("FromJSON() stack = %#v, want %#v", st, stack{withoutPC})
+			}
+		})
+	}
+
+	if _, err := FromJSON([]byte(`{"message": 1}`)); err == nil {
+		t.Error("FromJSON() error = nil, want error")
+	}
+	if _, err := FromJSON([]byte(`{"message": "x", "stack": ["bad"]}`)); err == nil {
+		t.Error("FromJSON() error = nil, want invalid frame error")
+	}
+}
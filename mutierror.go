@@ -2,6 +2,8 @@ package errorx
 
 import (
 	"errors"
+	"log/slog"
+	"strconv"
 )
 
 type customErrors []CustomError
@@ -25,6 +27,12 @@ func (e customErrors) Unwrap() []error {
 	return errs
 }
 
+// Errors returns the joined errors, for libraries such as go.uber.org/multierr
+// that read multi-errors through Errors() []error.
+func (e customErrors) Errors() []error {
+	return e.Unwrap()
+}
+
 func (e customErrors) Is(target error) bool {
 	targetMultiError, ok := target.(customErrors)
 	if !ok {
@@ -42,3 +50,14 @@ func (e customErrors) Is(target error) bool {
 	}
 	return true
 }
+
+func (e customErrors) LogValue() slog.Value {
+	causes := make([]slog.Attr, 0, len(e))
+	for i, err := range e {
+		causes = append(causes, slog.Any(strconv.Itoa(i), err))
+	}
+	return slog.GroupValue(
+		slog.String("message", e.Error()),
+		slog.Attr{Key: "causes", Value: slog.GroupValue(causes...)},
+	)
+}
@@ -1,6 +1,7 @@
 package errorx
 
 import (
+	"log/slog"
 	"testing"
 )
 
@@ -50,3 +51,16 @@ func Test_customErrors_Is(t *testing.T) {
 		})
 	}
 }
+
+func Test_customErrors_LogValue(t *testing.T) {
+	err := Join(New("err1").With("foo", "bar"), New("err2"))
+
+	got := err.LogValue()
+	want := slog.GroupValue(
+		slog.String("message", "err1\nerr2"),
+		slog.Group("causes", slog.Any("0", err[0]), slog.Any("1", err[1])),
+	)
+	if !got.Equal(want) {
+		t.Errorf("customErrors.LogValue() = %v, want %v", got, want)
+	}
+}
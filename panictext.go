@@ -0,0 +1,180 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// goroutineTrace is one goroutine of a panic or runtime.Stack dump.
+type goroutineTrace struct {
+	id        int
+	state     string
+	stack     stack
+	createdBy *frame
+	elided    bool
+}
+
+// parsePanicText extracts the panic message and the goroutines of a Go
+// traceback as printed on panic, on a fatal runtime error such as
+// "fatal error: concurrent map writes", or by runtime.Stack.
+func parsePanicText(text string) (string, []goroutineTrace) {
+	var (
+		messages   []string
+		goroutines []goroutineTrace
+		cur        *goroutineTrace
+		pending    *frame
+		createdBy  bool
+	)
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			cur, pending = nil, nil
+		case cur == nil && strings.HasPrefix(trimmed, "panic: "):
+			messages = append(messages, strings.TrimPrefix(trimmed, "panic: "))
+		case cur == nil && strings.HasPrefix(trimmed, "fatal error: "):
+			messages = append(messages, strings.TrimPrefix(trimmed, "fatal error: "))
+		case strings.HasPrefix(trimmed, "goroutine ") && strings.HasSuffix(trimmed, ":"):
+			g, ok := parseGoroutineHeader(trimmed)
+			if !ok {
+				continue
+			}
+			goroutines = append(goroutines, g)
+			cur, pending = &goroutines[len(goroutines)-1], nil
+		case cur == nil:
+		case strings.HasPrefix(trimmed, "...") && strings.Contains(trimmed, "frames elided"):
+			cur.elided = true
+		case pending != nil && strings.HasPrefix(line, "\t"):
+			pending.file, pending.line = parseFileLine(trimmed)
+			if createdBy {
+				cur.createdBy = pending
+			} else {
+				cur.stack = append(cur.stack, *pending)
+			}
+			pending = nil
+		case strings.HasPrefix(trimmed, "created by "):
+			name := strings.TrimPrefix(trimmed, "created by ")
+			if i := strings.Index(name, " in goroutine "); i >= 0 {
+				name = name[:i]
+			}
+			pending, createdBy = &frame{function: name}, true
+		default:
+			pending, createdBy = &frame{function: trimCallArgs(trimmed)}, false
+		}
+	}
+	return strings.Join(messages, "\n"), goroutines
+}
+
+// parseGoroutineHeader parses "goroutine 1 [running]:", including the
+// "goroutine 1 gp=0xc000002380 m=0 mp=0x5d8140 [running]:" variant.
+func parseGoroutineHeader(line string) (goroutineTrace, bool) {
+	rest := strings.TrimPrefix(line, "goroutine ")
+	idText, rest, ok := strings.Cut(rest, " ")
+	if !ok {
+		return goroutineTrace{}, false
+	}
+	id, err := strconv.Atoi(idText)
+	if err != nil {
+		return goroutineTrace{}, false
+	}
+	start, end := strings.IndexByte(rest, '['), strings.LastIndexByte(rest, ']')
+	if start < 0 || end < start {
+		return goroutineTrace{}, false
+	}
+	return goroutineTrace{id: id, state: rest[start+1 : end]}, true
+}
+
+// trimCallArgs turns "main.(*T).run(0xc000010000, {0x1, 0x2})" into
+// "main.(*T).run".
+func trimCallArgs(line string) string {
+	if !strings.HasSuffix(line, ")") {
+		return line
+	}
+	depth := 0
+	for i := len(line) - 1; i >= 0; i-- {
+		switch line[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth--; depth == 0 {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// parseFileLine parses "/src/main.go:12 +0x1d".
+func parseFileLine(line string) (string, int) {
+	if i := strings.Index(line, " +0x"); i >= 0 {
+		line = line[:i]
+	}
+	if i := strings.Index(line, " fp="); i >= 0 {
+		line = line[:i]
+	}
+	i := strings.LastIndexByte(line, ':')
+	if i < 0 {
+		return line, 0
+	}
+	n, err := strconv.Atoi(line[i+1:])
+	if err != nil {
+		return line, 0
+	}
+	return line[:i], n
+}
+
+func (g goroutineTrace) toError(message string) *customError {
+	data := map[string]any{
+		"goroutine":       g.id,
+		"goroutine_state": g.state,
+	}
+	if g.createdBy != nil {
+		b, _ := g.createdBy.MarshalText()
+		data["created_by"] = string(b)
+	}
+	if g.elided {
+		data["frames_elided"] = true
+	}
+	return &customError{
+		err:   errors.New(message),
+		stack: g.stack,
+		data:  data,
+	}
+}
+
+// FromPanicText builds a CustomError from the text of a Go panic or a
+// runtime.Stack dump. The stack is the one of the first goroutine, which
+// is the panicking one in panic output; goroutine id, state, "created by"
+// and elided frames are recorded in the data. It returns nil when text
+// contains no goroutine.
+func FromPanicText(text string) CustomError {
+	message, goroutines := parsePanicText(text)
+	if len(goroutines) == 0 {
+		return nil
+	}
+	g := goroutines[0]
+	if message == "" {
+		message = fmt.Sprintf("goroutine %d [%s]", g.id, g.state)
+	}
+	e := g.toError(message)
+	if len(goroutines) > 1 {
+		e.data["goroutines"] = len(goroutines)
+	}
+	return e
+}
+
+// GoroutinesFromText returns one CustomError per goroutine of a Go panic
+// or runtime.Stack dump, or nil when text contains no goroutine.
+func GoroutinesFromText(text string) CustomErrors {
+	_, goroutines := parsePanicText(text)
+	if len(goroutines) == 0 {
+		return nil
+	}
+	errs := make(customErrors, 0, len(goroutines))
+	for _, g := range goroutines {
+		errs = append(errs, g.toError(fmt.Sprintf("goroutine %d [%s]", g.id, g.state)))
+	}
+	return errs
+}
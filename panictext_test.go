@@ -0,0 +1,138 @@
+package errorx
+
+import (
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestFromPanicText(t *testing.T) {
+	text, err := os.ReadFile("testdata/panic.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FromPanicText(string(text))
+	if got == nil {
+		t.Fatal("FromPanicText() = nil")
+	}
+	if want := "runtime error: index out of range [5] with length 3 [recovered]\nruntime error: index out of range [5] with length 3"; got.Error() != want {
+		t.Errorf("FromPanicText().Error() = %q, want %q", got.Error(), want)
+	}
+	wantStack := stack{
+		{function: "panic", file: "/usr/local/go/src/runtime/panic.go", line: 770},
+		{function: "example.com/app/store.(*Store).Get", file: "/home/dev/app/store/store.go", line: 42},
+		{function: "example.com/app/api.handler.func1", file: "/home/dev/app/api/handler.go", line: 17},
+	}
+	if e := got.(*customError); !reflect.DeepEqual(e.stack, wantStack) {
+		t.Errorf("FromPanicText() stack = %#v, want %#v", e.stack, wantStack)
+	}
+	wantData := map[string]any{
+		"goroutine":       7,
+		"goroutine_state": "running",
+		"created_by":      "example.com/app/api.Serve /home/dev/app/api/serve.go:30",
+		"frames_elided":   true,
+		"goroutines":      2,
+	}
+	if data := Data(got); !reflect.DeepEqual(data, wantData) {
+		t.Errorf("FromPanicText() data = %v, want %v", data, wantData)
+	}
+
+	if got := FromPanicText("no traceback here"); got != nil {
+		t.Errorf("FromPanicText() = %v, want nil", got)
+	}
+}
+
+func TestFromPanicTextFatalError(t *testing.T) {
+	text, err := os.ReadFile("testdata/fatal.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FromPanicText(string(text))
+	if got == nil {
+		t.Fatal("FromPanicText() = nil")
+	}
+	if want := "concurrent map writes"; got.Error() != want {
+		t.Errorf("FromPanicText().Error() = %q, want %q", got.Error(), want)
+	}
+	wantStack := stack{
+		{function: "internal/runtime/maps.fatal", file: "/usr/local/go/src/runtime/panic.go", line: 1058},
+		{function: "main.main.func1", file: "/home/dev/app/main.go", line: 9},
+	}
+	if e := got.(*customError); !reflect.DeepEqual(e.stack, wantStack) {
+		t.Errorf("FromPanicText() stack = %#v, want %#v", e.stack, wantStack)
+	}
+	wantData := map[string]any{
+		"goroutine":       18,
+		"goroutine_state": "running",
+		"created_by":      "main.main /home/dev/app/main.go:7",
+		"goroutines":      2,
+	}
+	if data := Data(got); !reflect.DeepEqual(data, wantData) {
+		t.Errorf("FromPanicText() data = %v, want %v", data, wantData)
+	}
+}
+
+func TestGoroutinesFromText(t *testing.T) {
+	text, err := os.ReadFile("testdata/panic.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := GoroutinesFromText(string(text)).(customErrors)
+	if len(got) != 2 {
+		t.Fatalf("GoroutinesFromText() = %d goroutines, want 2", len(got))
+	}
+	if want := "goroutine 1 [chan receive, 3 minutes]"; got[1].Error() != want {
+		t.Errorf("GoroutinesFromText()[1] = %q, want %q", got[1].Error(), want)
+	}
+	wantStack := stack{{function: "main.main", file: "/home/dev/app/main.go", line: 12}}
+	if st := got[1].(*customError).stack; !reflect.DeepEqual(st, wantStack) {
+		t.Errorf("GoroutinesFromText()[1] stack = %#v, want %#v", st, wantStack)
+	}
+
+	if got := GoroutinesFromText(""); got != nil {
+		t.Errorf("GoroutinesFromText(\"\") = %v, want nil", got)
+	}
+}
+
+func TestFromPanicTextRuntimeStack(t *testing.T) {
+	got := FromPanicText(string(debug.Stack()))
+	if got == nil {
+		t.Fatal("FromPanicText(debug.Stack()) = nil")
+	}
+	if !strings.HasPrefix(got.Error(), "goroutine ") {
+		t.Errorf("FromPanicText().Error() = %q, want goroutine header", got.Error())
+	}
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	found := false
+	for _, f := range got.(*customError).stack {
+		if f.function == name && strings.HasSuffix(f.file, "panictext_test.go") && f.line > 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("FromPanicText() stack = %v, want frame for %s", got.(*customError).stack, name)
+	}
+}
+
+func Test_trimCallArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "main.main()", want: "main.main"},
+		{in: "main.(*T).run(0xc000010000, {0x1, 0x2})", want: "main.(*T).run"},
+		{in: "main.f[...](...)", want: "main.f[...]"},
+		{in: "main.noargs", want: "main.noargs"},
+	}
+	for _, tt := range tests {
+		if got := trimCallArgs(tt.in); got != tt.want {
+			t.Errorf("trimCallArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package errorx
+
+import "sync"
+
+// DefaultPublicMessage is shown to clients when neither the error nor its
+// code has a public message.
+var DefaultPublicMessage = "internal server error"
+
+var publicMessages = struct {
+	sync.RWMutex
+	byCode map[string]string
+}{byCode: make(map[string]string)}
+
+// RegisterPublicMessage sets the fallback public message for errors with
+// the given code.
+func RegisterPublicMessage(code string, message string) {
+	publicMessages.Lock()
+	defer publicMessages.Unlock()
+	publicMessages.byCode[code] = message
+}
+
+// WithPublicMessage sets the message shown to clients in place of err's
+// own message.
+func WithPublicMessage(err error, message string) CustomError {
+	return custom(err).withPublicMessage(message)
+}
+
+// PublicMessage returns the outermost message set with WithPublicMessage.
+// Otherwise it falls back to the message registered for the error's code,
+// then to DefaultPublicMessage. The internal message is never returned.
+func PublicMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var message string
+	walkCustom(err, func(e *customError) bool {
+		message = e.publicMessage
+		return message == ""
+	})
+	if message != "" {
+		return message
+	}
+
+	publicMessages.RLock()
+	defer publicMessages.RUnlock()
+	if message, ok := publicMessages.byCode[Code(err)]; ok {
+		return message
+	}
+	return DefaultPublicMessage
+}
+
+// PublicError is the client-facing view of an error. It carries no cause,
+// data or stack, so response encoders can marshal it as is.
+type PublicError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func Public(err error) *PublicError {
+	if err == nil {
+		return nil
+	}
+	return &PublicError{
+		Code:    Code(err),
+		Message: PublicMessage(err),
+	}
+}
+
+func (e *PublicError) Error() string {
+	if e == nil {
+		return ""
+	}
+	return e.Message
+}
@@ -0,0 +1,74 @@
+package errorx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPublicMessage(t *testing.T) {
+	RegisterPublicMessage("not_found", "resource not found")
+	t.Cleanup(func() {
+		publicMessages.Lock()
+		delete(publicMessages.byCode, "not_found")
+		publicMessages.Unlock()
+	})
+
+	inner := WithCode(New("user 42 missing in table users"), "not_found")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil error", err: nil, want: ""},
+		{name: "std error", err: errors.New("pq: connection refused"), want: DefaultPublicMessage},
+		{name: "code fallback", err: inner, want: "resource not found"},
+		{name: "unknown code", err: WithCode(New("boom"), "unknown"), want: DefaultPublicMessage},
+		{name: "explicit message", err: WithPublicMessage(New("boom"), "try again later"), want: "try again later"},
+		{
+			name: "outer wins",
+			err:  WithPublicMessage(Wrap(fmt.Errorf("outer: %w", WithPublicMessage(New("inner"), "inner message"))), "outer message"),
+			want: "outer message",
+		},
+		{
+			name: "through fmt wrap",
+			err:  fmt.Errorf("outer: %w", WithPublicMessage(New("inner"), "inner message")),
+			want: "inner message",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PublicMessage(tt.err); got != tt.want {
+				t.Errorf("PublicMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var nilErr *customError
+	if got := WithPublicMessage(nilErr, "message"); got != nil {
+		t.Errorf("WithPublicMessage(nil) = %v, want nil", got)
+	}
+}
+
+func TestPublic(t *testing.T) {
+	if got := Public(nil); got != nil {
+		t.Errorf("Public(nil) = %v, want nil", got)
+	}
+
+	err := WithPublicMessage(WithCode(New("db password rejected"),
+		"unavailable"),
+		"service unavailable").
+		With("dsn", "postgres://admin:secret@db")
+	b, jsonErr := json.Marshal(Public(err))
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	if got, want := string(b), `{"code":"unavailable","message":"service unavailable"}`; got != want {
+		t.Errorf("json.Marshal(Public()) = %s, want %s", got, want)
+	}
+	if got := Public(err).Error(); got != "service unavailable" {
+		t.Errorf("PublicError.Error() = %v, want service unavailable", got)
+	}
+}
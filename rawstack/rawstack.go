@@ -0,0 +1,64 @@
+// Package rawstack captures errorx stacks as bare program counters so
+// production binaries can ship them without symbol strings and resolve them
+// offline with the symbolize package.
+package rawstack
+
+import (
+	"debug/elf"
+	"os"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/ice-coldbell/errorx/internal/elfnote"
+)
+
+// Stack is a stack reduced to program counters plus what is needed to
+// resolve them later against the same binary.
+type Stack struct {
+	// BuildID is the Go build ID of the running binary. It is empty when
+	// the executable is not an ELF file or cannot be read.
+	BuildID string `json:"build_id,omitempty"`
+	// AnchorFunc and Anchor are the name and run-time entry address of a
+	// known function. Comparing Anchor with the function's address in the
+	// binary gives the load offset of position-independent executables.
+	AnchorFunc string    `json:"anchor_func"`
+	Anchor     uintptr   `json:"anchor"`
+	PCs        []uintptr `json:"pcs"`
+}
+
+// Of returns the deepest stack captured in err's wrap chain in raw form.
+func Of(err error) Stack {
+	anchor := reflect.ValueOf(Of).Pointer()
+	return Stack{
+		BuildID:    buildID(),
+		AnchorFunc: runtime.FuncForPC(anchor).Name(),
+		Anchor:     runtime.FuncForPC(anchor).Entry(),
+		PCs:        errorx.StackPCs(err),
+	}
+}
+
+// for test injection
+var osExecutable = os.Executable
+
+var (
+	buildIDOnce   sync.Once
+	cachedBuildID string
+)
+
+func buildID() string {
+	buildIDOnce.Do(func() {
+		name, err := osExecutable()
+		if err != nil {
+			return
+		}
+		f, err := elf.Open(name)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		cachedBuildID, _ = elfnote.BuildID(f)
+	})
+	return cachedBuildID
+}
@@ -0,0 +1,35 @@
+package rawstack
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func TestOf(t *testing.T) {
+	inner := errorx.New("inner")
+	err := errorx.Wrap(errors.Join(errors.New("std"), inner))
+
+	got := Of(err)
+	var want []uintptr
+	for _, f := range errorx.StackOf(inner) {
+		want = append(want, f.PC)
+	}
+	if !reflect.DeepEqual(got.PCs, want) {
+		t.Errorf("Of().PCs = %v, want deepest stack %v", got.PCs, want)
+	}
+	fn := runtime.FuncForPC(got.Anchor)
+	if fn == nil || fn.Name() != got.AnchorFunc || fn.Entry() != got.Anchor {
+		t.Errorf("Of() anchor = %s at %#x, want function entry", got.AnchorFunc, got.Anchor)
+	}
+	if runtime.GOOS == "linux" && got.BuildID == "" {
+		t.Error("Of().BuildID is empty")
+	}
+
+	if got := Of(errors.New("std")); len(got.PCs) != 0 {
+		t.Errorf("Of(std).PCs = %v, want empty", got.PCs)
+	}
+}
@@ -0,0 +1,62 @@
+package errorx
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// RedactedPlaceholder is written in place of sensitive values.
+const RedactedPlaceholder = "[REDACTED]"
+
+// Redacted holds a sensitive value. Every output path (fmt, slog, JSON and
+// text encoders) sees RedactedPlaceholder; only Unredacted returns the value.
+type Redacted[T any] struct {
+	value T
+}
+
+func Redact[T any](v T) Redacted[T] {
+	return Redacted[T]{value: v}
+}
+
+func (r Redacted[T]) Unredacted() T {
+	return r.value
+}
+
+func (r Redacted[T]) unredacted() any {
+	return r.value
+}
+
+func (r Redacted[T]) String() string {
+	return RedactedPlaceholder
+}
+
+func (r Redacted[T]) Format(s fmt.State, verb rune) {
+	io.WriteString(s, RedactedPlaceholder)
+}
+
+func (r Redacted[T]) LogValue() slog.Value {
+	return slog.StringValue(RedactedPlaceholder)
+}
+
+func (r Redacted[T]) MarshalText() ([]byte, error) {
+	return []byte(RedactedPlaceholder), nil
+}
+
+// WithSensitive adds a value under key that every output path redacts. It
+// is With(key, Redact(value)).
+func WithSensitive(err error, key string, value any) CustomError {
+	return custom(err).withSensitive(key, value)
+}
+
+// Unredacted is Data with every Redacted value replaced by the value it
+// holds. It is meant for internal debugging tools only.
+func Unredacted(err error) map[string]any {
+	data := Data(err)
+	for k, v := range data {
+		if r, ok := v.(interface{ unredacted() any }); ok {
+			data[k] = r.unredacted()
+		}
+	}
+	return data
+}
@@ -0,0 +1,60 @@
+package errorx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	secret := Redact("hunter2")
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q"} {
+		if got := fmt.Sprintf(format, secret); got != RedactedPlaceholder {
+			t.Errorf("fmt.Sprintf(%q) = %q, want %q", format, got, RedactedPlaceholder)
+		}
+	}
+
+	b, err := json.Marshal(map[string]any{"token": secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"token":"[REDACTED]"}`; got != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+
+	if got := secret.Unredacted(); got != "hunter2" {
+		t.Errorf("Redacted.Unredacted() = %v, want hunter2", got)
+	}
+}
+
+func Test_customError_WithSensitive(t *testing.T) {
+	err := WithSensitive(New("login failed").With("user", "alice"), "password", "hunter2")
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("msg", slog.Any("error", err))
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("LogValue() leaked sensitive value: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), RedactedPlaceholder) {
+		t.Errorf("LogValue() = %s, want %s", buf.String(), RedactedPlaceholder)
+	}
+
+	if got := fmt.Sprint(Data(err)); strings.Contains(got, "hunter2") {
+		t.Errorf("Data() leaked sensitive value: %s", got)
+	}
+
+	want := map[string]any{"user": "alice", "password": "hunter2"}
+	if got := Unredacted(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unredacted() = %v, want %v", got, want)
+	}
+
+	var nilErr *customError
+	if got := WithSensitive(nilErr, "password", "hunter2"); got != nil {
+		t.Errorf("WithSensitive(nil) = %v, want nil", got)
+	}
+}
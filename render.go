@@ -0,0 +1,152 @@
+package errorx
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// RenderOptions configures Render.
+type RenderOptions struct {
+	// SourceLines is the number of source lines shown before and after the
+	// line of each frame. Zero shows no source.
+	SourceLines int
+	// Modules limits source snippets to frames from packages within these
+	// module paths. Empty means the main module of the running binary.
+	Modules []string
+}
+
+// Render returns err in its %+v form, with a snippet of source around the
+// line of every frame from the selected modules. The line the frame points
+// at is marked with ">". Frames whose source file cannot be read, as in
+// production containers, are printed without a snippet.
+func Render(err error, opts RenderOptions) string {
+	if err == nil {
+		return ""
+	}
+	if len(opts.Modules) == 0 {
+		if info, ok := readBuildInfo(); ok && info.Main.Path != "" {
+			opts.Modules = []string{info.Main.Path}
+		}
+	}
+	var b strings.Builder
+	formatVerbose(&b, err, nil, &opts)
+	return b.String()
+}
+
+// writeSnippet writes the source lines around f, if f is selected by opts
+// and its file is readable.
+func (opts *RenderOptions) writeSnippet(w io.Writer, f frame) {
+	lines := opts.Snippet(f.export())
+	if len(lines) == 0 {
+		return
+	}
+	width := len(fmt.Sprint(lines[len(lines)-1].Number))
+	for _, l := range lines {
+		marker := " "
+		if l.Current {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "\n\t%s %*d | %s", marker, width, l.Number, l.Text)
+	}
+}
+
+// SourceLine is a line of a source snippet.
+type SourceLine struct {
+	Number int
+	Text   string
+	// Current marks the line the frame points at.
+	Current bool
+}
+
+// Snippet returns the source lines Render shows around f: SourceLines
+// lines before and after f.Line, read through a cache of recently used
+// files. It returns nil when SourceLines is zero, f is outside Modules or
+// the file cannot be read. Frames with a program counter are read from the
+// file it resolves to, so snippets survive filters such as TrimPaths.
+func (opts *RenderOptions) Snippet(f Frame) []SourceLine {
+	if opts.SourceLines <= 0 || f.Line <= 0 {
+		return nil
+	}
+	if len(opts.Modules) > 0 && !inPaths(packageName(f.Function), opts.Modules) {
+		return nil
+	}
+	file := f.File
+	if f.PC != 0 {
+		file = frameForPC(f.PC).file
+	}
+	lines := sources.lines(file)
+	if f.Line > len(lines) {
+		return nil
+	}
+	var out []SourceLine
+	for n := max(f.Line-opts.SourceLines, 1); n <= min(f.Line+opts.SourceLines, len(lines)); n++ {
+		out = append(out, SourceLine{Number: n, Text: lines[n-1], Current: n == f.Line})
+	}
+	return out
+}
+
+// for test injection
+var osReadFile = os.ReadFile
+
+// sources caches the most recently used source files by path. Files that
+// cannot be read are cached as nil so they are not retried.
+var sources = newSourceCache(64)
+
+type sourceCache struct {
+	sync.Mutex
+	size  int
+	files map[string]*list.Element
+	// order holds *sourceFile values, most recently used first.
+	order *list.List
+}
+
+type sourceFile struct {
+	path  string
+	lines []string
+}
+
+func newSourceCache(size int) *sourceCache {
+	return &sourceCache{size: size, files: make(map[string]*list.Element), order: list.New()}
+}
+
+func (c *sourceCache) lines(file string) []string {
+	if lines, ok := c.get(file); ok {
+		return lines
+	}
+	// Read without holding the lock, so one slow file does not stall
+	// every other lookup.
+	var lines []string
+	if b, err := osReadFile(file); err == nil {
+		lines = strings.Split(string(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))), "\n")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if e, ok := c.files[file]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*sourceFile).lines
+	}
+	c.files[file] = c.order.PushFront(&sourceFile{path: file, lines: lines})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.files, oldest.Value.(*sourceFile).path)
+	}
+	return lines
+}
+
+func (c *sourceCache) get(file string) ([]string, bool) {
+	c.Lock()
+	defer c.Unlock()
+	e, ok := c.files[file]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*sourceFile).lines, true
+}
@@ -0,0 +1,134 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.go")
+	src := "package app\n\nfunc f() error {\n\treturn errorx.New(\"boom\")\n}\n"
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sources = newSourceCache(64)
+	})
+
+	app := frame{function: "example.com/app.f", file: file, line: 4}
+	lib := frame{function: "example.com/lib.g", file: file, line: 2}
+	missing := frame{function: "example.com/app.h", file: filepath.Join(dir, "missing.go"), line: 7}
+	err := &customError{err: errors.New("boom"), stack: stack{app, lib, missing}}
+
+	tests := []struct {
+		name string
+		err  error
+		opts RenderOptions
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "no source lines",
+			err:  err,
+			opts: RenderOptions{Modules: []string{"example.com/app"}},
+			want: fmt.Sprintf("%+v", err),
+		},
+		{
+			name: "snippets",
+			err:  err,
+			opts: RenderOptions{SourceLines: 1, Modules: []string{"example.com/app"}},
+			want: "boom" +
+				fmt.Sprintf("\n%+v", app) +
+				"\n\t  3 | func f() error {" +
+				"\n\t> 4 | \treturn errorx.New(\"boom\")" +
+				"\n\t  5 | }" +
+				fmt.Sprintf("\n%+v", lib) +
+				fmt.Sprintf("\n%+v", missing),
+		},
+		{
+			name: "clamped to file",
+			err:  &customError{err: errors.New("boom"), stack: stack{lib}},
+			opts: RenderOptions{SourceLines: 5, Modules: []string{"example.com/lib"}},
+			want: "boom" +
+				fmt.Sprintf("\n%+v", lib) +
+				"\n\t  1 | package app" +
+				"\n\t> 2 | " +
+				"\n\t  3 | func f() error {" +
+				"\n\t  4 | \treturn errorx.New(\"boom\")" +
+				"\n\t  5 | }" +
+				"\n\t  6 | ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.err, tt.opts); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sourceCache(t *testing.T) {
+	t.Cleanup(func() { osReadFile = os.ReadFile })
+	reads := 0
+	osReadFile = func(name string) ([]byte, error) {
+		reads++
+		if name == "missing.go" {
+			return nil, os.ErrNotExist
+		}
+		return []byte("a\r\nb"), nil
+	}
+
+	c := newSourceCache(2)
+	for i := 0; i < 2; i++ {
+		if got := c.lines("ok.go"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("lines(ok.go) = %q", got)
+		}
+		if got := c.lines("missing.go"); got != nil {
+			t.Errorf("lines(missing.go) = %q, want nil", got)
+		}
+	}
+	if reads != 2 {
+		t.Errorf("read files %d times, want 2", reads)
+	}
+
+	c.lines("ok.go")
+	c.lines("other.go")
+	if _, ok := c.files["missing.go"]; ok || c.order.Len() != 2 {
+		t.Errorf("cache holds %d files, want missing.go evicted", c.order.Len())
+	}
+	c.lines("missing.go")
+	if reads != 4 {
+		t.Errorf("read files %d times, want 4 after eviction", reads)
+	}
+}
+
+func TestRender_filteredPaths(t *testing.T) {
+	defer SetStackFilters(StackFilters()...)
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+	SetStackFilters(func(s Stack) Stack {
+		out := make(Stack, len(s))
+		for i, f := range s {
+			f.File = "trimmed/" + filepath.Base(f.File)
+			out[i] = f
+		}
+		return out
+	})
+
+	err := New("boom") // snippet marker
+	got := Render(err, RenderOptions{SourceLines: 1, Modules: []string{"github.com/ice-coldbell/errorx"}})
+	if !strings.Contains(got, "trimmed/render_test.go:") || !strings.Contains(got, "> ") || !strings.Contains(got, "// snippet marker") {
+		t.Errorf("Render() with trimmed paths = %q, want a snippet", got)
+	}
+}
@@ -0,0 +1,411 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+// SyncPolicy decides when the journal calls fsync.
+type SyncPolicy int
+
+const (
+	// SyncEveryBatch syncs once after every Send.
+	SyncEveryBatch SyncPolicy = iota
+	// SyncEveryEvent syncs after every line.
+	SyncEveryEvent
+	// SyncNever leaves syncing to the operating system.
+	SyncNever
+)
+
+type JournalOptions struct {
+	// MaxSize rotates the file before it grows beyond this many bytes.
+	// Zero disables size-based rotation.
+	MaxSize int64
+	// MaxAge rotates the file once it has been open this long. Zero
+	// disables time-based rotation.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep. Zero keeps all.
+	MaxBackups int
+	// Compress gzips rotated files.
+	Compress bool
+	Sync     SyncPolicy
+}
+
+// Record is one line of a journal.
+type Record struct {
+	Err error
+	// Code is errorx.Code(Err) when the record was written.
+	Code        string
+	Fingerprint string
+	Time        time.Time
+	Hostname    string
+}
+
+func (r Record) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+	if r.Err != nil {
+		// Wrapping finds errorx errors behind fmt.Errorf and other plain
+		// wrappers, so their code, data, stack and causes are kept. The
+		// stack of the wrapper itself is the journal's and is dropped.
+		_, marshaler := r.Err.(json.Marshaler)
+		var b []byte
+		var err error
+		if marshaler {
+			b, err = json.Marshal(r.Err)
+		} else {
+			b, err = json.Marshal(errorx.Wrap(r.Err))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return nil, err
+		}
+		if !marshaler {
+			delete(fields, "stack")
+		}
+	}
+	if r.Code != "" {
+		var err error
+		if fields["code"], err = json.Marshal(r.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	for k, v := range map[string]any{
+		"fingerprint": r.Fingerprint,
+		"timestamp":   r.Time,
+		"hostname":    r.Hostname,
+	} {
+		if fields[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func (r *Record) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Message     *string   `json:"message"`
+		Code        string    `json:"code"`
+		Fingerprint string    `json:"fingerprint"`
+		Time        time.Time `json:"timestamp"`
+		Hostname    string    `json:"hostname"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var err error
+	if v.Message != nil {
+		cErr, jsonErr := errorx.FromJSON(b)
+		if jsonErr != nil {
+			return jsonErr
+		}
+		err = cErr
+	}
+	*r = Record{
+		Err:         err,
+		Code:        v.Code,
+		Fingerprint: v.Fingerprint,
+		Time:        v.Time,
+		Hostname:    v.Hostname,
+	}
+	return nil
+}
+
+// Journal is a Sink appending one JSON Record per line to a local file.
+type Journal struct {
+	path string
+	opts JournalOptions
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+var _ Sink = &Journal{}
+
+func NewJournal(path string, opts JournalOptions) (*Journal, error) {
+	j := &Journal{path: path, opts: opts}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return errorx.Wrap(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errorx.Wrap(err)
+	}
+	j.file, j.size, j.opened = f, info.Size(), timeNow()
+
+	// Terminate a line torn by a crash so the next record starts cleanly.
+	if j.size > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, j.size-1); err != nil {
+			return errorx.Wrap(err)
+		}
+		if last[0] != '\n' {
+			n, err := f.Write([]byte{'\n'})
+			j.size += int64(n)
+			if err != nil {
+				return errorx.Wrap(err)
+			}
+		}
+	}
+	return nil
+}
+
+func (j *Journal) Send(ctx context.Context, events []Event) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return errorx.New("journal is closed").With("path", j.path)
+	}
+
+	var errs []error
+	for _, ev := range events {
+		line, err := json.Marshal(Record{
+			Err:         ev.Err,
+			Code:        ev.Code,
+			Fingerprint: ev.Fingerprint,
+			Time:        ev.Time,
+			Hostname:    ev.Hostname,
+		})
+		if err != nil {
+			errs = append(errs, errorx.Wrap(err).With("fingerprint", ev.Fingerprint))
+			continue
+		}
+		line = append(line, '\n')
+
+		if j.shouldRotate(int64(len(line))) {
+			if err := j.rotate(); err != nil {
+				errs = append(errs, err)
+				if j.file == nil {
+					return errorx.Join(errs...)
+				}
+			}
+		}
+		n, err := j.file.Write(line)
+		j.size += int64(n)
+		if err != nil {
+			return errorx.Join(append(errs, errorx.Wrap(err))...)
+		}
+		if j.opts.Sync == SyncEveryEvent {
+			if err := j.file.Sync(); err != nil {
+				return errorx.Join(append(errs, errorx.Wrap(err))...)
+			}
+		}
+	}
+	if j.opts.Sync == SyncEveryBatch {
+		if err := j.file.Sync(); err != nil {
+			errs = append(errs, errorx.Wrap(err))
+		}
+	}
+	if errs := errorx.Join(errs...); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+func (j *Journal) shouldRotate(n int64) bool {
+	if j.size == 0 {
+		return false
+	}
+	if j.opts.MaxSize > 0 && j.size+n > j.opts.MaxSize {
+		return true
+	}
+	return j.opts.MaxAge > 0 && timeNow().Sub(j.opened) >= j.opts.MaxAge
+}
+
+// backupLayout is the timestamp appended to rotated file names.
+const backupLayout = "20060102T150405.000000000"
+
+// backupGlob matches the timestamp of backupLayout.
+var backupGlob = strings.NewReplacer("0", "[0-9]", "1", "[0-9]", "2", "[0-9]", "3", "[0-9]",
+	"4", "[0-9]", "5", "[0-9]", "6", "[0-9]").Replace(backupLayout)
+
+// rotate renames the current file to <name>-<timestamp><ext>, opens a fresh
+// file, optionally gzips the rotated one and prunes old backups. On error
+// the journal keeps writing to the active file, reopening it if needed; it
+// is left closed only if no file can be opened.
+func (j *Journal) rotate() error {
+	if err := j.file.Sync(); err != nil {
+		return errorx.Wrap(err)
+	}
+	if err := j.file.Close(); err != nil {
+		j.file = nil
+		return j.reopen(errorx.Wrap(err))
+	}
+	j.file = nil
+
+	ext := filepath.Ext(j.path)
+	prefix := strings.TrimSuffix(j.path, ext)
+	rotated := prefix + "-" + timeNow().UTC().Format(backupLayout) + ext
+	if err := os.Rename(j.path, rotated); err != nil {
+		return j.reopen(errorx.Wrap(err))
+	}
+	if err := j.open(); err != nil {
+		if os.Rename(rotated, j.path) == nil {
+			return j.reopen(err)
+		}
+		return err
+	}
+
+	var errs []error
+	if err := syncDir(filepath.Dir(j.path)); err != nil {
+		errs = append(errs, err)
+	}
+	if j.opts.Compress {
+		if err := compressFile(rotated); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := j.prune(prefix, ext); err != nil {
+		errs = append(errs, err)
+	}
+	if errs := errorx.Join(errs...); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// reopen opens the active file again after a failed rotation and returns
+// cause, joined with the open error if any.
+func (j *Journal) reopen(cause error) error {
+	if err := j.open(); err != nil {
+		return errorx.Join(cause, err)
+	}
+	return cause
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return errorx.Wrap(err)
+	}
+	defer d.Close()
+	return errorx.Wrap(d.Sync())
+}
+
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return errorx.Wrap(err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return errorx.Wrap(err)
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		return errorx.Wrap(err)
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return errorx.Wrap(err)
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return errorx.Wrap(err)
+	}
+	if err := dst.Close(); err != nil {
+		return errorx.Wrap(err)
+	}
+	return errorx.Wrap(os.Remove(name))
+}
+
+func (j *Journal) prune(prefix string, ext string) error {
+	if j.opts.MaxBackups <= 0 {
+		return nil
+	}
+	pattern := prefix + "-" + backupGlob + ext
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return errorx.Wrap(err)
+	}
+	compressed, err := filepath.Glob(pattern + ".gz")
+	if err != nil {
+		return errorx.Wrap(err)
+	}
+	matches = append(matches, compressed...)
+	// Timestamps sort lexically, so the oldest backups come first.
+	sort.Strings(matches)
+	for len(matches) > j.opts.MaxBackups {
+		if err := os.Remove(matches[0]); err != nil {
+			return errorx.Wrap(err)
+		}
+		matches = matches[1:]
+	}
+	return nil
+}
+
+// Close syncs and closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	f := j.file
+	j.file = nil
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errorx.Wrap(err)
+	}
+	return errorx.Wrap(f.Close())
+}
+
+// ReadJournal decodes every record of a journal, transparently
+// decompressing gzipped files. Malformed lines, such as a line torn by a
+// crash, are skipped and counted in skipped.
+func ReadJournal(r io.Reader) (records []Record, skipped int, err error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, errorx.Wrap(err)
+	}
+	if bytes.HasPrefix(b, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, 0, errorx.Wrap(err)
+		}
+		if b, err = io.ReadAll(zr); err != nil {
+			return nil, 0, errorx.Wrap(err)
+		}
+	}
+
+	for _, line := range bytes.Split(b, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			skipped++
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, skipped, nil
+}
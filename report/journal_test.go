@@ -0,0 +1,296 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func readJournalFile(t *testing.T, name string) []Record {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, skipped, err := ReadJournal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 0 {
+		t.Errorf("ReadJournal(%s) skipped %d lines", name, skipped)
+	}
+	return records
+}
+
+func TestJournal(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, err := NewJournal(path, JournalOptions{Sync: SyncEveryEvent})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	custom := errorx.WithSensitive(errorx.WithCode(errorx.New("user not found"), "not_found").With("user_id", 42), "email", "a@b.c")
+	events := []Event{NewEvent(custom), NewEvent(errors.New("std error"))}
+	if err := j.Send(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Send(context.Background(), events); err == nil {
+		t.Error("Journal.Send() after Close error = nil, want error")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "a@b.c") {
+		t.Errorf("journal leaked sensitive value: %s", b)
+	}
+
+	records := readJournalFile(t, path)
+	if len(records) != 2 {
+		t.Fatalf("ReadJournal() = %d records, want 2", len(records))
+	}
+	got := records[0]
+	if got.Err.Error() != "user not found" || errorx.Code(got.Err) != "not_found" {
+		t.Errorf("record error = %v (code %q), want user not found (not_found)", got.Err, errorx.Code(got.Err))
+	}
+	if got.Fingerprint != events[0].Fingerprint || !got.Time.Equal(now) {
+		t.Errorf("record = %+v, want fingerprint %v at %v", got, events[0].Fingerprint, now)
+	}
+	if data := errorx.Data(got.Err); data["user_id"] != float64(42) || data["email"] != errorx.RedactedPlaceholder {
+		t.Errorf("record data = %v", data)
+	}
+	if records[1].Err.Error() != "std error" {
+		t.Errorf("record error = %v, want std error", records[1].Err)
+	}
+}
+
+func TestJournal_Rotate(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	t.Cleanup(func() { timeNow = time.Now })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "errors.jsonl")
+	j, err := NewJournal(path, JournalOptions{MaxSize: 1, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	for i := 0; i < 4; i++ {
+		if err := j.Send(context.Background(), []Event{NewEvent(errorx.New("test"))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "errors-*.jsonl.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(backups)
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want 2 gzipped files", backups)
+	}
+	for _, name := range append(backups, path) {
+		if got := readJournalFile(t, name); len(got) != 1 {
+			t.Errorf("%s has %d records, want 1", name, len(got))
+		}
+	}
+}
+
+func TestJournal_MaxAge(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	dir := t.TempDir()
+	j, err := NewJournal(filepath.Join(dir, "errors.jsonl"), JournalOptions{MaxAge: time.Hour, Sync: SyncNever})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	send := func() {
+		if err := j.Send(context.Background(), []Event{NewEvent(errorx.New("test"))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send()
+	send()
+	now = now.Add(time.Hour)
+	send()
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "errors-*.jsonl"))
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want 1", backups)
+	}
+	if got := readJournalFile(t, backups[0]); len(got) != 2 {
+		t.Errorf("backup has %d records, want 2", len(got))
+	}
+}
+
+func TestJournal_TornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	if err := os.WriteFile(path, []byte(`{"message":"torn`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	j, err := NewJournal(path, JournalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Send(context.Background(), []Event{NewEvent(errorx.New("after crash"))}); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	b, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "after crash") {
+		t.Errorf("journal = %q, want torn line followed by a complete record", b)
+	}
+
+	f, _ := os.Open(path)
+	defer f.Close()
+	records, skipped, err := ReadJournal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 1 || len(records) != 1 || records[0].Err.Error() != "after crash" {
+		t.Errorf("ReadJournal() = %v, %d skipped; want the record after the torn line and 1 skipped", records, skipped)
+	}
+}
+
+func TestJournal_PruneSiblings(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	t.Cleanup(func() { timeNow = time.Now })
+
+	dir := t.TempDir()
+	sibling := filepath.Join(dir, "errors-api.jsonl")
+	if err := os.WriteFile(sibling, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	j, err := NewJournal(filepath.Join(dir, "errors.jsonl"), JournalOptions{MaxSize: 1, MaxBackups: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	for i := 0; i < 3; i++ {
+		if err := j.Send(context.Background(), []Event{NewEvent(errorx.New("test"))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(sibling); err != nil {
+		t.Errorf("prune removed sibling journal: %v", err)
+	}
+	backups, _ := filepath.Glob(filepath.Join(dir, "errors-2*.jsonl"))
+	if len(backups) != 1 {
+		t.Errorf("backups = %v, want 1", backups)
+	}
+}
+
+func TestJournal_RotateError(t *testing.T) {
+	timeNow = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "errors.jsonl")
+	j, err := NewJournal(path, JournalOptions{MaxSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	send := func() error {
+		return j.Send(context.Background(), []Event{NewEvent(errorx.New("test"))})
+	}
+	if err := send(); err != nil {
+		t.Fatal(err)
+	}
+	// A directory at the rotated name makes the rename fail.
+	if err := os.Mkdir(filepath.Join(dir, "errors-20240102T030405.000000000.jsonl"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "errors-20240102T030405.000000000.jsonl", "x"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := send(); err == nil {
+		t.Fatal("Journal.Send() error = nil, want rotation error")
+	}
+	if err := send(); err == nil {
+		t.Fatal("Journal.Send() error = nil, want rotation error")
+	}
+	if got := readJournalFile(t, path); len(got) != 3 {
+		t.Errorf("journal has %d records after failed rotations, want 3", len(got))
+	}
+}
+
+func TestRecord_NilErr(t *testing.T) {
+	b, err := json.Marshal(Record{Fingerprint: "fp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r Record
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Err != nil || r.Fingerprint != "fp" {
+		t.Errorf("Record round trip = %+v, want nil Err and fingerprint fp", r)
+	}
+}
+
+func TestRecord_WrappedErr(t *testing.T) {
+	inner := errorx.WithCode(errorx.New("not found"), "not_found").With("id", 7)
+	ev := NewEvent(fmt.Errorf("get user: %w", inner))
+	b, err := json.Marshal(Record{Err: ev.Err, Code: ev.Code, Fingerprint: ev.Fingerprint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r Record
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Code != "not_found" || errorx.Code(r.Err) != "not_found" {
+		t.Errorf("Record code = %q, errorx.Code = %q; want not_found", r.Code, errorx.Code(r.Err))
+	}
+	if r.Err.Error() != "get user: not found" || errorx.Data(r.Err)["id"] != float64(7) {
+		t.Errorf("Record error = %v with data %v", r.Err, errorx.Data(r.Err))
+	}
+	if st := errorx.StackOf(r.Err); len(st) == 0 || !strings.HasSuffix(st[0].Function, "TestRecord_WrappedErr") {
+		t.Errorf("Record stack = %v, want the stack of the inner error", st)
+	}
+}
+
+func TestJournal_UnencodableData(t *testing.T) {
+	j, err := NewJournal(filepath.Join(t.TempDir(), "errors.jsonl"), JournalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if err := j.Send(context.Background(), []Event{NewEvent(errorx.New("boom").With("cb", func() {}))}); err != nil {
+		t.Errorf("Journal.Send() = %v, want the event written", err)
+	}
+}
@@ -0,0 +1,308 @@
+package report
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+// Backpressure decides what Report does when the queue is full.
+type Backpressure int
+
+const (
+	// DropNewest discards the event being reported.
+	DropNewest Backpressure = iota
+	// DropOldest discards the oldest queued event to make room.
+	DropOldest
+	// Block waits for room until the context passed to Report is done.
+	Block
+)
+
+type Options struct {
+	// QueueSize bounds the number of events waiting to be sent. Default 1024.
+	QueueSize int
+	// BatchSize is the maximum number of events per Sink.Send. Default 100.
+	BatchSize int
+	// FlushInterval sends partial batches after this long. Default 1s.
+	FlushInterval time.Duration
+	// DedupWindow drops events whose fingerprint was reported within the
+	// window. Zero disables deduplication.
+	DedupWindow time.Duration
+	// SampleRates keeps the given fraction (0 to 1) of events per error
+	// code. Codes without an entry use DefaultSampleRate.
+	SampleRates map[string]float64
+	// DefaultSampleRate applies to codes missing from SampleRates. Values
+	// of zero or below mean 1, keeping every event.
+	DefaultSampleRate float64
+	Backpressure      Backpressure
+	// OnError is called with errors returned by sinks.
+	OnError func(error)
+}
+
+// for test injection
+var randFloat64 = rand.Float64
+
+// Pipeline is a Reporter that deduplicates, samples and enriches errors and
+// forwards them in batches to its sinks from a background goroutine.
+type Pipeline struct {
+	opts  Options
+	sinks []Sink
+	queue chan Event
+	flush chan chan struct{}
+	done  chan struct{}
+	exit  chan struct{}
+
+	// closing releases Reports blocked on a full queue once Close starts.
+	// Reports hold sendMu for reading until their event is queued, so Close
+	// can wait for them before run sends the last batch.
+	closing   chan struct{}
+	closeOnce sync.Once
+	sendMu    sync.RWMutex
+	closed    bool
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+	// seen lists lastSeen updates oldest first so expired fingerprints can
+	// be evicted without scanning the map.
+	seen []seenEntry
+
+	dropped atomic.Uint64
+}
+
+var _ Reporter = &Pipeline{}
+
+func NewPipeline(opts Options, sinks ...Sink) *Pipeline {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.DefaultSampleRate <= 0 {
+		opts.DefaultSampleRate = 1
+	}
+	p := &Pipeline{
+		opts:     opts,
+		sinks:    sinks,
+		queue:    make(chan Event, opts.QueueSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+		exit:     make(chan struct{}),
+		closing:  make(chan struct{}),
+		lastSeen: make(map[string]time.Time),
+	}
+	go p.run()
+	return p
+}
+
+func (p *Pipeline) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	ev := NewEvent(err)
+
+	p.sendMu.RLock()
+	defer p.sendMu.RUnlock()
+	if p.closed || !p.admit(ev) {
+		p.dropped.Add(1)
+		return
+	}
+	if !p.enqueue(ctx, ev) {
+		p.dropped.Add(1)
+		p.forget(ev)
+	}
+}
+
+// Dropped returns the number of events dropped by deduplication, sampling,
+// backpressure or because the pipeline was closed.
+func (p *Pipeline) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+type seenEntry struct {
+	fingerprint string
+	time        time.Time
+}
+
+// admit reports whether ev passes deduplication and sampling. An admitted
+// event is recorded as seen; Report calls forget if it is not queued.
+func (p *Pipeline) admit(ev Event) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.opts.DedupWindow > 0 {
+		p.evict(ev.Time)
+		if last, ok := p.lastSeen[ev.Fingerprint]; ok && ev.Time.Sub(last) < p.opts.DedupWindow {
+			return false
+		}
+	}
+
+	rate, ok := p.opts.SampleRates[ev.Code]
+	if !ok {
+		rate = p.opts.DefaultSampleRate
+	}
+	if rate < 1 && randFloat64() >= rate {
+		return false
+	}
+
+	if p.opts.DedupWindow > 0 {
+		p.lastSeen[ev.Fingerprint] = ev.Time
+		p.seen = append(p.seen, seenEntry{fingerprint: ev.Fingerprint, time: ev.Time})
+	}
+	return true
+}
+
+// forget undoes the dedup record of an admitted event that was dropped.
+func (p *Pipeline) forget(ev Event) {
+	if p.opts.DedupWindow <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if last, ok := p.lastSeen[ev.Fingerprint]; ok && last.Equal(ev.Time) {
+		delete(p.lastSeen, ev.Fingerprint)
+	}
+}
+
+// evict removes fingerprints last seen a full window before now.
+func (p *Pipeline) evict(now time.Time) {
+	for len(p.seen) > 0 && now.Sub(p.seen[0].time) >= p.opts.DedupWindow {
+		e := p.seen[0]
+		p.seen = p.seen[1:]
+		if last, ok := p.lastSeen[e.fingerprint]; ok && last.Equal(e.time) {
+			delete(p.lastSeen, e.fingerprint)
+		}
+	}
+}
+
+// enqueue reports whether ev was queued. DropOldest always queues ev but
+// may drop older events to make room.
+func (p *Pipeline) enqueue(ctx context.Context, ev Event) bool {
+	switch p.opts.Backpressure {
+	case Block:
+		select {
+		case p.queue <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		case <-p.closing:
+			return false
+		}
+	case DropOldest:
+		for {
+			select {
+			case p.queue <- ev:
+				return true
+			default:
+			}
+			select {
+			case <-p.queue:
+				p.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case p.queue <- ev:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+func (p *Pipeline) run() {
+	defer close(p.exit)
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, p.opts.BatchSize)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		for _, sink := range p.sinks {
+			if err := sink.Send(context.Background(), batch); err != nil && p.opts.OnError != nil {
+				p.opts.OnError(err)
+			}
+		}
+		batch = make([]Event, 0, p.opts.BatchSize)
+	}
+	add := func(ev Event) {
+		batch = append(batch, ev)
+		if len(batch) >= p.opts.BatchSize {
+			send()
+		}
+	}
+	drain := func() {
+		for {
+			select {
+			case ev := <-p.queue:
+				add(ev)
+			default:
+				send()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case ev := <-p.queue:
+			add(ev)
+		case <-ticker.C:
+			send()
+		case ack := <-p.flush:
+			drain()
+			close(ack)
+		case <-p.done:
+			drain()
+			return
+		}
+	}
+}
+
+// Flush sends every queued event and waits until the sinks returned or ctx
+// is done.
+func (p *Pipeline) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case p.flush <- ack:
+	case <-p.exit:
+		return nil
+	case <-ctx.Done():
+		return errorx.Wrap(ctx.Err())
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return errorx.Wrap(ctx.Err())
+	}
+}
+
+// Close stops accepting events, sends the queued ones and waits for the
+// background goroutine to exit or ctx to be done.
+func (p *Pipeline) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.closing)
+		p.sendMu.Lock()
+		p.closed = true
+		p.sendMu.Unlock()
+		close(p.done)
+	})
+
+	select {
+	case <-p.exit:
+		return nil
+	case <-ctx.Done():
+		return errorx.Wrap(ctx.Err())
+	}
+}
@@ -0,0 +1,291 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]Event
+	// When block is set, Send signals sending and waits for block to be
+	// closed before recording the batch.
+	sending chan struct{}
+	block   chan struct{}
+}
+
+func newBlockingSink() *recordingSink {
+	return &recordingSink{sending: make(chan struct{}, 1), block: make(chan struct{})}
+}
+
+func (s *recordingSink) Send(ctx context.Context, events []Event) error {
+	if s.block != nil {
+		select {
+		case s.sending <- struct{}{}:
+		default:
+		}
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *recordingSink) events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []Event
+	for _, b := range s.batches {
+		events = append(events, b...)
+	}
+	return events
+}
+
+func newTestError(code string) error {
+	return errorx.WithCode(errorx.New("test error"), code)
+}
+
+func TestPipeline_Dedup(t *testing.T) {
+	now := time.Unix(0, 0)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	sink := &recordingSink{}
+	p := NewPipeline(Options{DedupWindow: time.Minute}, sink)
+	defer p.Close(context.Background())
+
+	ctx := context.Background()
+	p.Report(ctx, newTestError("a"))
+	p.Report(ctx, newTestError("a"))
+	p.Report(ctx, newTestError("b"))
+	now = now.Add(time.Minute)
+	p.Report(ctx, newTestError("a"))
+
+	if err := p.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var codes []string
+	for _, ev := range sink.events() {
+		codes = append(codes, ev.Code)
+	}
+	if len(codes) != 3 || codes[0] != "a" || codes[1] != "b" || codes[2] != "a" {
+		t.Errorf("sent codes = %v, want [a b a]", codes)
+	}
+	if got := p.Dropped(); got != 1 {
+		t.Errorf("Pipeline.Dropped() = %v, want 1", got)
+	}
+}
+
+func TestPipeline_Sampling(t *testing.T) {
+	samples := []float64{0.1, 0.9, 0.1, 0.9}
+	randFloat64 = func() float64 {
+		v := samples[0]
+		samples = samples[1:]
+		return v
+	}
+	t.Cleanup(func() { randFloat64 = rand.Float64 })
+
+	sink := &recordingSink{}
+	p := NewPipeline(Options{SampleRates: map[string]float64{"noisy": 0.5}}, sink)
+	defer p.Close(context.Background())
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		p.Report(ctx, newTestError("noisy"))
+	}
+	p.Report(ctx, newTestError("rare"))
+	p.Report(ctx, nil)
+
+	if err := p.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sink.events()); got != 3 {
+		t.Errorf("sent %d events, want 3", got)
+	}
+	if got := p.Dropped(); got != 2 {
+		t.Errorf("Pipeline.Dropped() = %v, want 2", got)
+	}
+}
+
+func TestPipeline_Batching(t *testing.T) {
+	sink := &recordingSink{}
+	p := NewPipeline(Options{BatchSize: 2}, sink)
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		p.Report(ctx, newTestError("a"))
+	}
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	var sizes []int
+	for _, b := range sink.batches {
+		sizes = append(sizes, len(b))
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("batch sizes = %v, want [2 2 1]", sizes)
+	}
+
+	p.Report(ctx, newTestError("a"))
+	if got := p.Dropped(); got != 1 {
+		t.Errorf("Pipeline.Dropped() after Close = %v, want 1", got)
+	}
+	if err := p.Flush(ctx); err != nil {
+		t.Errorf("Pipeline.Flush() after Close = %v, want nil", err)
+	}
+}
+
+func TestPipeline_Backpressure(t *testing.T) {
+	tests := []struct {
+		name         string
+		backpressure Backpressure
+		wantCodes    []string
+	}{
+		{name: "drop newest", backpressure: DropNewest, wantCodes: []string{"first", "1", "2"}},
+		{name: "drop oldest", backpressure: DropOldest, wantCodes: []string{"first", "2", "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := newBlockingSink()
+			p := NewPipeline(Options{QueueSize: 2, BatchSize: 1, Backpressure: tt.backpressure}, sink)
+
+			ctx := context.Background()
+			p.Report(ctx, newTestError("first"))
+			<-sink.sending
+			p.Report(ctx, newTestError("1"))
+			p.Report(ctx, newTestError("2"))
+			p.Report(ctx, newTestError("3"))
+			close(sink.block)
+
+			if err := p.Close(ctx); err != nil {
+				t.Fatal(err)
+			}
+			var codes []string
+			for _, ev := range sink.events() {
+				codes = append(codes, ev.Code)
+			}
+			if len(codes) != len(tt.wantCodes) {
+				t.Fatalf("sent codes = %v, want %v", codes, tt.wantCodes)
+			}
+			for i := range codes {
+				if codes[i] != tt.wantCodes[i] {
+					t.Errorf("sent codes = %v, want %v", codes, tt.wantCodes)
+					break
+				}
+			}
+			if got := p.Dropped(); got != 1 {
+				t.Errorf("Pipeline.Dropped() = %v, want 1", got)
+			}
+		})
+	}
+}
+
+func TestPipeline_BlockContext(t *testing.T) {
+	sink := newBlockingSink()
+	p := NewPipeline(Options{QueueSize: 1, BatchSize: 1, Backpressure: Block}, sink)
+
+	p.Report(context.Background(), newTestError("first"))
+	<-sink.sending
+	p.Report(context.Background(), newTestError("queued"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	p.Report(ctx, newTestError("blocked"))
+	if got := p.Dropped(); got != 1 {
+		t.Errorf("Pipeline.Dropped() = %v, want 1", got)
+	}
+
+	if err := p.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Pipeline.Flush() = %v, want context.DeadlineExceeded", err)
+	}
+	close(sink.block)
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sink.events()); got != 2 {
+		t.Errorf("sent %d events, want 2", got)
+	}
+}
+
+func TestPipeline_OnError(t *testing.T) {
+	sinkErr := errors.New("sink failed")
+	var got []error
+	p := NewPipeline(Options{OnError: func(err error) { got = append(got, err) }},
+		SinkFunc(func(context.Context, []Event) error { return sinkErr }))
+
+	p.Report(context.Background(), newTestError("a"))
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != sinkErr {
+		t.Errorf("OnError got %v, want [%v]", got, sinkErr)
+	}
+}
+
+func TestPipeline_DedupAfterDrop(t *testing.T) {
+	sink := newBlockingSink()
+	p := NewPipeline(Options{QueueSize: 1, BatchSize: 1, DedupWindow: time.Hour}, sink)
+
+	ctx := context.Background()
+	p.Report(ctx, newTestError("first"))
+	<-sink.sending
+	p.Report(ctx, newTestError("queued"))
+	p.Report(ctx, newTestError("dropped"))
+	close(sink.block)
+	if err := p.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// "dropped" never reached a sink, so it is not a duplicate.
+	p.Report(ctx, newTestError("dropped"))
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var codes []string
+	for _, ev := range sink.events() {
+		codes = append(codes, ev.Code)
+	}
+	if len(codes) != 3 || codes[2] != "dropped" {
+		t.Errorf("sent codes = %v, want [first queued dropped]", codes)
+	}
+}
+
+func TestPipeline_CloseBlockedReport(t *testing.T) {
+	sink := newBlockingSink()
+	p := NewPipeline(Options{QueueSize: 1, BatchSize: 1, Backpressure: Block}, sink)
+
+	ctx := context.Background()
+	p.Report(ctx, newTestError("first"))
+	<-sink.sending
+	p.Report(ctx, newTestError("queued"))
+
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		p.Report(ctx, newTestError("blocked"))
+	}()
+	closed := make(chan error)
+	go func() { closed <- p.Close(ctx) }()
+
+	<-reported
+	close(sink.block)
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sink.events()); got != 2 {
+		t.Errorf("sent %d events, want 2", got)
+	}
+	if got := p.Dropped(); got != 1 {
+		t.Errorf("Pipeline.Dropped() = %v, want 1", got)
+	}
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"context"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+// Reporter receives errors to report.
+type Reporter interface {
+	Report(ctx context.Context, err error)
+}
+
+// Sink delivers batches of events to a backend such as Sentry or a file.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, events []Event) error
+
+func (f SinkFunc) Send(ctx context.Context, events []Event) error {
+	return f(ctx, events)
+}
+
+// Event is an error enriched for reporting.
+type Event struct {
+	Err         error
+	Fingerprint string
+	Code        string
+	Time        time.Time
+	Hostname    string
+	Build       BuildInfo
+}
+
+type BuildInfo struct {
+	Path      string
+	Version   string
+	Revision  string
+	GoVersion string
+}
+
+// for test injection
+var (
+	timeNow        = time.Now
+	osHostname     = os.Hostname
+	readBuildInfo  = debug.ReadBuildInfo
+	hostnameOnce   sync.Once
+	cachedHostname string
+	buildInfoOnce  sync.Once
+	cachedBuild    BuildInfo
+)
+
+func NewEvent(err error) Event {
+	return Event{
+		Err:         err,
+		Fingerprint: errorx.Fingerprint(err),
+		Code:        errorx.Code(err),
+		Time:        timeNow(),
+		Hostname:    hostname(),
+		Build:       buildInfo(),
+	}
+}
+
+func hostname() string {
+	hostnameOnce.Do(func() {
+		cachedHostname, _ = osHostname()
+	})
+	return cachedHostname
+}
+
+func buildInfo() BuildInfo {
+	buildInfoOnce.Do(func() {
+		info, ok := readBuildInfo()
+		if !ok {
+			return
+		}
+		cachedBuild = BuildInfo{
+			Path:      info.Main.Path,
+			Version:   info.Main.Version,
+			GoVersion: info.GoVersion,
+		}
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				cachedBuild.Revision = s.Value
+			}
+		}
+	})
+	return cachedBuild
+}
@@ -0,0 +1,53 @@
+package report
+
+import (
+	"errors"
+	"os"
+	"runtime/debug"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ice-coldbell/errorx"
+)
+
+func TestNewEvent(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	osHostname = func() (string, error) { return "test-host", nil }
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.21.5",
+			Main:      debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+			Settings:  []debug.BuildSetting{{Key: "vcs.revision", Value: "abc123"}},
+		}, true
+	}
+	hostnameOnce, buildInfoOnce = sync.Once{}, sync.Once{}
+	t.Cleanup(func() {
+		timeNow, osHostname, readBuildInfo = time.Now, os.Hostname, debug.ReadBuildInfo
+		hostnameOnce, buildInfoOnce = sync.Once{}, sync.Once{}
+	})
+
+	err := errorx.WithCode(errorx.New("not found"), "not_found")
+	got := NewEvent(err)
+	want := Event{
+		Err:         err,
+		Fingerprint: errorx.Fingerprint(err),
+		Code:        "not_found",
+		Time:        now,
+		Hostname:    "test-host",
+		Build: BuildInfo{
+			Path:      "example.com/app",
+			Version:   "v1.2.3",
+			Revision:  "abc123",
+			GoVersion: "go1.21.5",
+		},
+	}
+	if got != want {
+		t.Errorf("NewEvent() = %+v, want %+v", got, want)
+	}
+
+	if got := NewEvent(errors.New("std")); got.Code != "" || got.Fingerprint == "" {
+		t.Errorf("NewEvent(std) = %+v, want fingerprint without code", got)
+	}
+}
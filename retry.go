@@ -0,0 +1,127 @@
+package errorx
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// WithRetryable marks err as worth retrying or not.
+func WithRetryable(err error, retryable bool) CustomError {
+	return custom(err).withRetryable(retryable)
+}
+
+// WithRetryAfter marks err retryable after at least d.
+func WithRetryAfter(err error, d time.Duration) CustomError {
+	return custom(err).withRetryAfter(d)
+}
+
+// IsRetryable reports whether err is worth retrying. The outermost
+// WithRetryable or WithRetryAfter in the chain decides; otherwise
+// context.DeadlineExceeded and net.Error timeouts are retryable.
+func IsRetryable(err error) bool {
+	var retryable bool
+	walk(err, func(err error) bool {
+		if e, ok := err.(*customError); ok && e != nil && e.retryable != nil {
+			retryable = *e.retryable
+			return false
+		}
+		if err == context.DeadlineExceeded {
+			retryable = true
+			return false
+		}
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			retryable = true
+			return false
+		}
+		return true
+	})
+	return retryable
+}
+
+// RetryAfter returns the outermost delay set with WithRetryAfter.
+func RetryAfter(err error) (time.Duration, bool) {
+	var d time.Duration
+	walkCustom(err, func(e *customError) bool {
+		d = e.retryAfter
+		return d == 0
+	})
+	return d, d > 0
+}
+
+type RetryPolicy struct {
+	// MaxAttempts includes the first call. Values below 1 mean a single call.
+	MaxAttempts int
+	// InitialDelay is the delay after the first failed attempt.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts. Zero means no cap.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after every attempt. Values below 1 mean 2.
+	Multiplier float64
+	// Jitter randomly shortens each delay by up to this fraction (0 to 1).
+	Jitter float64
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	d := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * randFloat64()
+	}
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+// for test injection
+var (
+	timeAfter   = time.After
+	randFloat64 = rand.Float64
+)
+
+// Retry calls fn until it succeeds, returns an error that is not
+// IsRetryable, the policy runs out of attempts or ctx is done. On failure it
+// returns a CustomErrors holding every attempt's error, each with "attempt"
+// and "delay" data.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(context.Context) error) error {
+	var errs customErrors
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var delay time.Duration
+		last := attempt >= policy.MaxAttempts || !IsRetryable(err)
+		if !last {
+			if d, ok := RetryAfter(err); ok {
+				delay = d
+			} else {
+				delay = policy.delay(attempt)
+			}
+		}
+		errs = append(errs, &customError{
+			err:   err,
+			stack: callers(3),
+			data:  map[string]any{"attempt": attempt, "delay": delay},
+		})
+		if last {
+			return errs
+		}
+
+		select {
+		case <-ctx.Done():
+			return append(errs, WrapDepth(ctx.Err(), 4))
+		case <-timeAfter(delay):
+		}
+	}
+}
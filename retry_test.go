@@ -0,0 +1,204 @@
+package errorx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type timeoutError struct{ timeout bool }
+
+func (e timeoutError) Error() string   { return "timeout" }
+func (e timeoutError) Timeout() bool   { return e.timeout }
+func (e timeoutError) Temporary() bool { return false }
+
+var _ net.Error = timeoutError{}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil error", err: nil, want: false},
+		{name: "std error", err: errors.New("std"), want: false},
+		{name: "marked retryable", err: WithRetryable(New("x"), true), want: true},
+		{name: "retry after", err: WithRetryAfter(New("x"), time.Second), want: true},
+		{name: "deadline exceeded", err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: true},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "net timeout", err: Wrap(timeoutError{timeout: true}), want: true},
+		{name: "net error without timeout", err: timeoutError{timeout: false}, want: false},
+		{name: "outer wins", err: WithRetryable(Wrap(fmt.Errorf("x: %w", context.DeadlineExceeded)), false), want: false},
+		{name: "joined", err: Join(errors.New("std"), WithRetryable(New("x"), true)), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var nilErr *customError
+	if got := WithRetryable(nilErr, true); got != nil {
+		t.Errorf("WithRetryable(nil) = %v, want nil", got)
+	}
+	if got := WithRetryAfter(nilErr, time.Second); got != nil {
+		t.Errorf("WithRetryAfter(nil) = %v, want nil", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := RetryAfter(errors.New("std")); ok || d != 0 {
+		t.Errorf("RetryAfter(std) = %v, %v, want 0, false", d, ok)
+	}
+	err := fmt.Errorf("x: %w", WithRetryAfter(New("x"), time.Minute))
+	if d, ok := RetryAfter(err); !ok || d != time.Minute {
+		t.Errorf("RetryAfter() = %v, %v, want 1m, true", d, ok)
+	}
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	randFloat64 = func() float64 { return 0.5 }
+	t.Cleanup(func() { randFloat64 = rand.Float64 })
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first attempt", policy: RetryPolicy{InitialDelay: time.Second}, attempt: 1, want: time.Second},
+		{name: "default multiplier", policy: RetryPolicy{InitialDelay: time.Second}, attempt: 3, want: 4 * time.Second},
+		{name: "custom multiplier", policy: RetryPolicy{InitialDelay: time.Second, Multiplier: 3}, attempt: 3, want: 9 * time.Second},
+		{name: "max delay", policy: RetryPolicy{InitialDelay: time.Second, MaxDelay: 3 * time.Second}, attempt: 5, want: 3 * time.Second},
+		{name: "jitter", policy: RetryPolicy{InitialDelay: time.Second, Jitter: 0.5}, attempt: 1, want: 750 * time.Millisecond},
+		{name: "overflow", policy: RetryPolicy{InitialDelay: time.Hour}, attempt: 1000, want: time.Duration(1<<63 - 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.delay(tt.attempt); got != tt.want {
+				t.Errorf("RetryPolicy.delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var slept []time.Duration
+	timeAfter = func(d time.Duration) <-chan time.Time {
+		slept = append(slept, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+	t.Cleanup(func() { timeAfter = time.After })
+
+	policy := RetryPolicy{MaxAttempts: 4, InitialDelay: time.Second}
+	retryable := WithRetryable(New("unavailable"), true)
+	permanent := New("bad request")
+
+	tests := []struct {
+		name       string
+		results    []error
+		wantCalls  int
+		wantSlept  []time.Duration
+		wantErrs   []error
+		wantDelays []time.Duration
+	}{
+		{
+			name:      "first call succeeds",
+			results:   []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "succeeds after retries",
+			results:   []error{retryable, retryable, nil},
+			wantCalls: 3,
+			wantSlept: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:       "stops on non-retryable error",
+			results:    []error{retryable, permanent},
+			wantCalls:  2,
+			wantSlept:  []time.Duration{time.Second},
+			wantErrs:   []error{retryable, permanent},
+			wantDelays: []time.Duration{time.Second, 0},
+		},
+		{
+			name:       "runs out of attempts",
+			results:    []error{retryable, retryable, retryable, retryable},
+			wantCalls:  4,
+			wantSlept:  []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			wantErrs:   []error{retryable, retryable, retryable, retryable},
+			wantDelays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 0},
+		},
+		{
+			name:       "honors retry after",
+			results:    []error{WithRetryAfter(New("throttled"), time.Minute), permanent},
+			wantCalls:  2,
+			wantSlept:  []time.Duration{time.Minute},
+			wantDelays: []time.Duration{time.Minute, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slept = nil
+			calls := 0
+			err := Retry(context.Background(), policy, func(context.Context) error {
+				calls++
+				return tt.results[calls-1]
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("Retry() calls = %v, want %v", calls, tt.wantCalls)
+			}
+			if !reflect.DeepEqual(slept, tt.wantSlept) {
+				t.Errorf("Retry() slept = %v, want %v", slept, tt.wantSlept)
+			}
+			if tt.wantDelays == nil {
+				if err != nil {
+					t.Errorf("Retry() = %v, want nil", err)
+				}
+				return
+			}
+			errs, ok := err.(customErrors)
+			if !ok || len(errs) != len(tt.wantDelays) {
+				t.Fatalf("Retry() = %#v, want %d attempt errors", err, len(tt.wantDelays))
+			}
+			for i, e := range errs {
+				data := e.(*customError).data
+				if data["attempt"] != i+1 || data["delay"] != tt.wantDelays[i] {
+					t.Errorf("Retry() attempt %d data = %v, want attempt %d, delay %v", i, data, i+1, tt.wantDelays[i])
+				}
+				if tt.wantErrs != nil && !errors.Is(e, tt.wantErrs[i]) {
+					t.Errorf("Retry() attempt %d = %v, want %v", i, e, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRetryContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	timeAfter = func(d time.Duration) <-chan time.Time {
+		cancel()
+		return nil
+	}
+	t.Cleanup(func() { timeAfter = time.After })
+
+	err := Retry(ctx, RetryPolicy{MaxAttempts: 3}, func(context.Context) error {
+		return WithRetryable(New("unavailable"), true)
+	})
+	errs, ok := err.(customErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Retry() = %v, want attempt error and context error", err)
+	}
+	if !errors.Is(errs[1], context.Canceled) {
+		t.Errorf("Retry() last error = %v, want context.Canceled", errs[1])
+	}
+}
@@ -0,0 +1,245 @@
+package errorx
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// StackFilter rewrites a stack before it is formatted, encoded, logged or
+// returned by StackOf. Filters must not modify the stack they are given.
+type StackFilter func(Stack) Stack
+
+// LogStack adds the stack to LogValue as a "stack" attribute. It is
+// encoded after the filters set with SetStackFilters.
+var LogStack = false
+
+var stackFilters = struct {
+	sync.RWMutex
+	filters []StackFilter
+}{}
+
+// SetStackFilters replaces the filters applied to every emitted stack.
+// They run in order. Captured stacks are never modified, so filters can be
+// changed at any time.
+//
+//	errorx.SetStackFilters(
+//		errorx.DropRuntime(),
+//		errorx.CollapseModules(),
+//		errorx.TrimPaths(),
+//	)
+func SetStackFilters(filters ...StackFilter) {
+	stackFilters.Lock()
+	defer stackFilters.Unlock()
+	stackFilters.filters = filters
+}
+
+// StackFilters returns the filters set with SetStackFilters.
+func StackFilters() []StackFilter {
+	stackFilters.RLock()
+	defer stackFilters.RUnlock()
+	return append([]StackFilter(nil), stackFilters.filters...)
+}
+
+func (s stack) filtered() stack {
+	stackFilters.RLock()
+	filters := stackFilters.filters
+	stackFilters.RUnlock()
+	if len(filters) == 0 || len(s) == 0 {
+		return s
+	}
+
+	st := make(Stack, len(s))
+	for i, f := range s {
+		st[i] = f.export()
+	}
+	for _, filter := range filters {
+		st = filter(st)
+	}
+	out := make(stack, len(st))
+	for i, f := range st {
+		out[i] = f.internal()
+	}
+	return out
+}
+
+// DropPackages drops frames from the given packages and their
+// subpackages.
+func DropPackages(packages ...string) StackFilter {
+	return func(s Stack) Stack {
+		var out Stack
+		for _, f := range s {
+			if !inPaths(f.Package, packages) {
+				out = append(out, f)
+			}
+		}
+		return out
+	}
+}
+
+// DropRuntime drops frames from the runtime and testing packages.
+func DropRuntime() StackFilter {
+	return DropPackages("runtime", "testing")
+}
+
+// KeepModules keeps only frames from packages within the given module
+// paths. A stack without any such frame is returned unchanged, so errors
+// raised entirely outside those modules keep their trace.
+func KeepModules(modules ...string) StackFilter {
+	return func(s Stack) Stack {
+		var out Stack
+		for _, f := range s {
+			if inPaths(f.Package, modules) {
+				out = append(out, f)
+			}
+		}
+		if len(out) == 0 {
+			return s
+		}
+		return out
+	}
+}
+
+// KeepMainModule is KeepModules for the main module of the running binary.
+// It keeps every frame when the binary carries no module information.
+func KeepMainModule() StackFilter {
+	info, ok := readBuildInfo()
+	if !ok || info.Main.Path == "" {
+		return func(s Stack) Stack { return s }
+	}
+	return KeepModules(info.Main.Path)
+}
+
+// CollapseModules replaces each run of consecutive frames from the same
+// third-party module with the run's first frame, which is the one called
+// by the code around it. Standard library and main module frames are kept.
+func CollapseModules() StackFilter {
+	var main string
+	var modules []string
+	if info, ok := readBuildInfo(); ok {
+		main = info.Main.Path
+		modules = append(modules, main)
+		for _, dep := range info.Deps {
+			modules = append(modules, dep.Path)
+		}
+	}
+	return func(s Stack) Stack {
+		var out Stack
+		var last string
+		for _, f := range s {
+			module := moduleOf(f.Package, modules)
+			if module == main {
+				module = ""
+			}
+			if module != "" && module == last {
+				continue
+			}
+			last = module
+			out = append(out, f)
+		}
+		return out
+	}
+}
+
+// TrimPaths trims the module cache, GOPATH and GOROOT prefixes from file
+// paths, leaving paths like "github.com/a/b@v1.0.0/b.go" and
+// "net/http/server.go".
+func TrimPaths() StackFilter {
+	prefixes := sourcePrefixes()
+	return func(s Stack) Stack {
+		out := make(Stack, len(s))
+		for i, f := range s {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(f.File, prefix) {
+					f.File = f.File[len(prefix):]
+					break
+				}
+			}
+			out[i] = f
+		}
+		return out
+	}
+}
+
+// for test injection
+var (
+	readBuildInfo = debug.ReadBuildInfo
+	osGetenv      = os.Getenv
+)
+
+// sourcePrefixes returns the directories source files are read from,
+// most specific first, each with a trailing slash.
+func sourcePrefixes() []string {
+	var gopath []string
+	for _, dir := range filepath.SplitList(osGetenv("GOPATH")) {
+		if dir != "" {
+			gopath = append(gopath, dir)
+		}
+	}
+	if len(gopath) == 0 {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = append(gopath, filepath.Join(home, "go"))
+		}
+	}
+
+	var prefixes []string
+	if modcache := osGetenv("GOMODCACHE"); modcache != "" {
+		prefixes = append(prefixes, modcache)
+	} else if len(gopath) > 0 {
+		prefixes = append(prefixes, filepath.Join(gopath[0], "pkg", "mod"))
+	}
+	for _, dir := range gopath {
+		prefixes = append(prefixes, filepath.Join(dir, "src"))
+	}
+	if goroot := gorootSrc(); goroot != "" {
+		prefixes = append(prefixes, goroot)
+	}
+	for i, prefix := range prefixes {
+		prefixes[i] = filepath.ToSlash(prefix) + "/"
+	}
+	return prefixes
+}
+
+// gorootSrc returns the GOROOT source directory the binary was built from,
+// derived from the recorded file of a runtime function so it is right even
+// when GOROOT has moved since.
+func gorootSrc() string {
+	pc := reflect.ValueOf(runtime.Callers).Pointer()
+	file, _ := runtime.FuncForPC(pc).FileLine(pc)
+	dir, ok := strings.CutSuffix(file, "/runtime/"+filepath.Base(file))
+	if !ok || dir == "" {
+		return ""
+	}
+	return dir
+}
+
+// moduleOf returns the longest module path containing pkg. Packages outside
+// the known modules are their own module, except for the standard library.
+func moduleOf(pkg string, modules []string) string {
+	var module string
+	for _, m := range modules {
+		if len(m) > len(module) && inPaths(pkg, []string{m}) {
+			module = m
+		}
+	}
+	if module != "" {
+		return module
+	}
+	if first, _, _ := strings.Cut(pkg, "/"); !strings.Contains(first, ".") {
+		return ""
+	}
+	return pkg
+}
+
+func inPaths(pkg string, paths []string) bool {
+	for _, p := range paths {
+		if pkg == p || strings.HasPrefix(pkg, p+"/") {
+			return true
+		}
+	}
+	return false
+}
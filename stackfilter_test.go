@@ -0,0 +1,207 @@
+package errorx
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func testStack(functions ...string) Stack {
+	st := make(Stack, len(functions))
+	for i, fn := range functions {
+		st[i] = Frame{Function: fn, Package: packageName(fn)}
+	}
+	return st
+}
+
+func functions(st Stack) []string {
+	var out []string
+	for _, f := range st {
+		out = append(out, f.Function)
+	}
+	return out
+}
+
+func fakeBuildInfo(t *testing.T, main string, deps ...string) {
+	t.Cleanup(func() { readBuildInfo = debug.ReadBuildInfo })
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		info := &debug.BuildInfo{Main: debug.Module{Path: main}}
+		for _, dep := range deps {
+			info.Deps = append(info.Deps, &debug.Module{Path: dep})
+		}
+		return info, true
+	}
+}
+
+func TestStackFilters(t *testing.T) {
+	fakeBuildInfo(t, "example.com/app", "github.com/go-chi/chi/v5", "github.com/lib/pq")
+
+	st := testStack(
+		"example.com/app/store.(*DB).Get",
+		"github.com/lib/pq.(*conn).query",
+		"github.com/lib/pq.(*conn).Query",
+		"example.com/app/api.handler",
+		"github.com/go-chi/chi/v5/middleware.Logger.func1",
+		"github.com/go-chi/chi/v5.(*Mux).ServeHTTP",
+		"net/http.serverHandler.ServeHTTP",
+		"net/http.(*conn).serve",
+		"testing.tRunner",
+		"runtime.goexit",
+	)
+	tests := []struct {
+		name   string
+		filter StackFilter
+		want   []string
+	}{
+		{
+			name:   "drop runtime",
+			filter: DropRuntime(),
+			want:   functions(st[:8]),
+		},
+		{
+			name:   "drop packages",
+			filter: DropPackages("net", "github.com/go-chi/chi/v5"),
+			want:   append(functions(st[:4]), functions(st[8:])...),
+		},
+		{
+			name:   "keep main module",
+			filter: KeepMainModule(),
+			want:   []string{"example.com/app/store.(*DB).Get", "example.com/app/api.handler"},
+		},
+		{
+			name:   "keep modules",
+			filter: KeepModules("github.com/lib/pq"),
+			want:   functions(st[1:3]),
+		},
+		{
+			name:   "keep nothing",
+			filter: KeepModules("example.com/other"),
+			want:   functions(st),
+		},
+		{
+			name:   "collapse modules",
+			filter: CollapseModules(),
+			want: []string{
+				"example.com/app/store.(*DB).Get",
+				"github.com/lib/pq.(*conn).query",
+				"example.com/app/api.handler",
+				"github.com/go-chi/chi/v5/middleware.Logger.func1",
+				"net/http.serverHandler.ServeHTTP",
+				"net/http.(*conn).serve",
+				"testing.tRunner",
+				"runtime.goexit",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := functions(tt.filter(st)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimPaths(t *testing.T) {
+	t.Cleanup(func() { osGetenv = os.Getenv })
+	osGetenv = func(key string) string {
+		return map[string]string{
+			"GOPATH":     "/home/u/go",
+			"GOMODCACHE": "/cache/mod",
+		}[key]
+	}
+
+	goroot := gorootSrc()
+	if goroot == "" {
+		t.Fatal("gorootSrc() = \"\", want the GOROOT source directory")
+	}
+	st := Stack{
+		{File: "/cache/mod/github.com/lib/pq@v1.10.9/conn.go"},
+		{File: "/home/u/go/src/example.com/legacy/legacy.go"},
+		{File: goroot + "/net/http/server.go"},
+		{File: "/work/app/main.go"},
+	}
+	want := []string{
+		"github.com/lib/pq@v1.10.9/conn.go",
+		"example.com/legacy/legacy.go",
+		"net/http/server.go",
+		"/work/app/main.go",
+	}
+	got := TrimPaths()(st)
+	for i := range want {
+		if got[i].File != want[i] {
+			t.Errorf("TrimPaths()[%d].File = %q, want %q", i, got[i].File, want[i])
+		}
+	}
+	if st[0].File != "/cache/mod/github.com/lib/pq@v1.10.9/conn.go" {
+		t.Errorf("TrimPaths() modified its input: %q", st[0].File)
+	}
+}
+
+func TestSetStackFilters(t *testing.T) {
+	defer SetStackFilters()
+	defer func() { LogStack = false }()
+
+	err := &customError{
+		err: errors.New("filtered"),
+		stack: stack{
+			{function: "example.com/app.f", file: "/app/f.go", line: 1},
+			{function: "runtime.goexit", file: "/go/src/runtime/asm.s", line: 2},
+		},
+	}
+	SetStackFilters(DropRuntime())
+	if got := StackFilters(); len(got) != 1 {
+		t.Errorf("StackFilters() = %d filters, want 1", len(got))
+	}
+
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, "runtime.goexit") {
+		t.Errorf("%%+v = %q, want runtime frames dropped", got)
+	}
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var v struct{ Stack []string }
+	if jerr := json.Unmarshal(b, &v); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if want := []string{"example.com/app.f /app/f.go:1"}; !reflect.DeepEqual(v.Stack, want) {
+		t.Errorf("json stack = %q, want %q", v.Stack, want)
+	}
+
+	if got := functions(StackOf(err)); !reflect.DeepEqual(got, []string{"example.com/app.f"}) {
+		t.Errorf("StackOf() = %q", got)
+	}
+
+	LogStack = true
+	var stackAttr slog.Value
+	for _, a := range err.LogValue().Group() {
+		if a.Key == "stack" {
+			stackAttr = a.Value
+		}
+	}
+	if got, _ := stackAttr.Any().(encoding.TextMarshaler).MarshalText(); string(got) != "example.com/app.f /app/f.go:1" {
+		t.Errorf("LogValue() stack = %q, want one frame", got)
+	}
+
+	if got, _ := err.stack[1].MarshalText(); string(got) != "runtime.goexit /go/src/runtime/asm.s:2" {
+		t.Errorf("frame.MarshalText() of a dropped frame = %q", got)
+	}
+	err.stack[0].pc, err.stack[1].pc = 1, 2
+	if got := err.StackTrace(); !reflect.DeepEqual(got, []uintptr{1}) {
+		t.Errorf("customError.StackTrace() = %v, want [1]", got)
+	}
+
+	SetStackFilters()
+	if got := StackOf(err); len(got) != 2 {
+		t.Errorf("StackOf() without filters = %v, want 2 frames", got)
+	}
+}
@@ -1,6 +1,7 @@
 package errorx
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"path"
@@ -15,15 +16,16 @@ type frame struct {
 	line     int
 }
 
+// frameForPC resolves a pc returned by runtime.Callers. CallersFrames backs
+// up into the call instruction, so a return address that falls into code
+// inlined after the call is still attributed to the calling frame.
 func frameForPC(pc uintptr) frame {
 	f := frame{pc: pc}
-	fn := runtime.FuncForPC(pc)
-	if fn == nil {
+	if pc == 0 {
 		return f
 	}
-
-	f.function = fn.Name()
-	f.file, f.line = fn.FileLine(pc)
+	rf, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	f.function, f.file, f.line = rf.Function, rf.File, rf.Line
 	return f
 }
 
@@ -53,8 +55,17 @@ func (f frame) Format(s fmt.State, verb rune) {
 	}
 }
 
+// MarshalText encodes the frame as "function file:line" after the filters
+// set with SetStackFilters. A frame the filters drop is encoded unchanged.
 func (f frame) MarshalText() ([]byte, error) {
-	return []byte(fmt.Sprintf("%s %s:%d", f.function, f.file, f.line)), nil
+	if st := (stack{f}).filtered(); len(st) == 1 {
+		f = st[0]
+	}
+	return f.text(), nil
+}
+
+func (f frame) text() []byte {
+	return []byte(fmt.Sprintf("%s %s:%d", f.function, f.file, f.line))
 }
 
 type stack []frame
@@ -93,10 +104,36 @@ func (s stack) formatSlice(st fmt.State, verb rune) {
 	io.WriteString(st, "]")
 }
 
+// MarshalText encodes the filtered stack with one frame per line.
+func (s stack) MarshalText() ([]byte, error) {
+	var b []byte
+	for i, f := range s.filtered() {
+		if i > 0 {
+			b = append(b, '\n')
+		}
+		b = append(b, f.text()...)
+	}
+	return b, nil
+}
+
+// MarshalJSON encodes the filtered stack as an array of frame texts.
+func (s stack) MarshalJSON() ([]byte, error) {
+	st := s.filtered()
+	out := make([]string, len(st))
+	for i, f := range st {
+		out[i] = string(f.text())
+	}
+	return json.Marshal(out)
+}
+
+// StackTrace returns the program counters of s. Frames without one, such
+// as frames decoded from JSON or rewritten by a filter, are skipped.
 func (s stack) StackTrace() []uintptr {
-	pcs := make([]uintptr, len(s))
-	for i := 0; i < len(s); i++ {
-		pcs[i] = s[i].pc
+	pcs := make([]uintptr, 0, len(s))
+	for _, f := range s {
+		if f.pc != 0 {
+			pcs = append(pcs, f.pc)
+		}
 	}
 	return pcs
 }
@@ -107,7 +144,7 @@ var runtimeCallers = runtime.Callers
 func callers(skip int) stack {
 	const depth = 32
 	var pcs [depth]uintptr
-	n := runtimeCallers(2+skip, pcs[:])
+	n := runtimeCallers(skip, pcs[:])
 	var st stack
 	for i := range pcs[0:n] {
 		st = append(st, frameForPC(pcs[i]))
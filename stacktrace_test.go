@@ -291,3 +291,18 @@ func Test_callers(t *testing.T) {
 		})
 	}
 }
+
+func TestCallersStartAtCaller(t *testing.T) {
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+
+	const want = "github.com/ice-coldbell/errorx.TestCallersStartAtCaller"
+	for name, err := range map[string]error{
+		"New":  New("x"),
+		"Wrap": Wrap(fmt.Errorf("x")),
+	} {
+		if st := StackOf(err); len(st) == 0 || st[0].Function != want {
+			t.Errorf("StackOf(%s()) = %v, want it to start at %s", name, st, want)
+		}
+	}
+}
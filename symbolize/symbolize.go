@@ -0,0 +1,211 @@
+// Package symbolize resolves raw program counters captured with
+// rawstack.Of against the ELF binary that produced them.
+package symbolize
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"debug/gosym"
+	"fmt"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/ice-coldbell/errorx/internal/elfnote"
+	"github.com/ice-coldbell/errorx/rawstack"
+)
+
+// Frame is a resolved stack frame. Inlined reports whether Function was
+// inlined into its caller; it is only known for binaries with DWARF.
+type Frame struct {
+	PC       uintptr
+	Function string
+	File     string
+	Line     int
+	Inlined  bool
+}
+
+// Binary is an opened ELF executable.
+type Binary struct {
+	file    *elf.File
+	buildID string
+	table   *gosym.Table
+	dwarf   *dwarf.Data
+}
+
+func Open(name string) (*Binary, error) {
+	f, err := elf.Open(name)
+	if err != nil {
+		return nil, errorx.Wrap(err)
+	}
+	b, err := newBinary(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func newBinary(f *elf.File) (*Binary, error) {
+	buildID, err := elfnote.BuildID(f)
+	if err != nil {
+		return nil, errorx.Wrap(err)
+	}
+	pclntab := f.Section(".gopclntab")
+	text := f.Section(".text")
+	if pclntab == nil || text == nil {
+		return nil, errorx.New("binary has no .gopclntab or .text section")
+	}
+	pcln, err := pclntab.Data()
+	if err != nil {
+		return nil, errorx.Wrap(err)
+	}
+	var symtab []byte
+	if s := f.Section(".gosymtab"); s != nil {
+		if symtab, err = s.Data(); err != nil {
+			return nil, errorx.Wrap(err)
+		}
+	}
+	table, err := gosym.NewTable(symtab, gosym.NewLineTable(pcln, text.Addr))
+	if err != nil {
+		return nil, errorx.Wrap(err)
+	}
+	// Binaries linked with -w have no DWARF; inlined frames are then
+	// reported as part of the function they were inlined into.
+	d, _ := f.DWARF()
+	return &Binary{file: f, buildID: buildID, table: table, dwarf: d}, nil
+}
+
+func (b *Binary) Close() error {
+	return b.file.Close()
+}
+
+func (b *Binary) BuildID() string {
+	return b.buildID
+}
+
+// Symbolize resolves raw. It fails if raw was captured from a different
+// build or if the load offset derived from the anchor is not page aligned.
+func (b *Binary) Symbolize(raw rawstack.Stack) ([]Frame, error) {
+	if raw.BuildID != "" && raw.BuildID != b.buildID {
+		return nil, errorx.New("build ID mismatch").
+			With("want", raw.BuildID).
+			With("got", b.buildID)
+	}
+	offset, err := b.loadOffset(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []Frame
+	for _, pc := range raw.PCs {
+		frames = append(frames, b.resolve(pc, pc-offset))
+	}
+	return frames, nil
+}
+
+func (b *Binary) loadOffset(raw rawstack.Stack) (uintptr, error) {
+	if raw.AnchorFunc == "" {
+		return 0, nil
+	}
+	fn := b.table.LookupFunc(raw.AnchorFunc)
+	if fn == nil {
+		return 0, errorx.New("anchor function not found").With("function", raw.AnchorFunc)
+	}
+	offset := raw.Anchor - uintptr(fn.Entry)
+	if offset&0xfff != 0 {
+		return 0, errorx.New(fmt.Sprintf("base address mismatch: load offset %#x is not page aligned", offset)).
+			With("anchor", raw.Anchor).
+			With("entry", fn.Entry)
+	}
+	return offset, nil
+}
+
+// resolve maps a return address to its frame. runtime.Callers already
+// records one address per logical frame, inlined calls included, so every
+// address yields exactly one frame.
+func (b *Binary) resolve(pc uintptr, addr uintptr) Frame {
+	// Return addresses point after the call; look up the call itself.
+	lookup := uint64(addr) - 1
+	file, line, fn := b.table.PCToLine(lookup)
+	if fn == nil {
+		return Frame{PC: pc}
+	}
+	f := Frame{PC: pc, Function: fn.Name, File: file, Line: line}
+	if name, ok := b.inlinedFunction(lookup); ok {
+		f.Function, f.Inlined = name, true
+	}
+	return f
+}
+
+// inlinedFunction returns the innermost function inlined at pc according to
+// the DWARF inlined subroutine entries.
+func (b *Binary) inlinedFunction(pc uint64) (string, bool) {
+	if b.dwarf == nil {
+		return "", false
+	}
+	r := b.dwarf.Reader()
+	if _, err := r.SeekPC(pc); err != nil {
+		return "", false
+	}
+
+	var (
+		name  string
+		found bool
+	)
+	depth := 0
+	for {
+		e, err := r.Next()
+		if err != nil || e == nil {
+			break
+		}
+		if e.Tag == 0 {
+			if depth--; depth < 0 {
+				break
+			}
+			continue
+		}
+		covers := false
+		if e.Tag == dwarf.TagSubprogram || e.Tag == dwarf.TagInlinedSubroutine {
+			covers = b.covers(e, pc)
+		}
+		if e.Tag == dwarf.TagInlinedSubroutine && covers {
+			name, found = b.originName(e), true
+		}
+		if !e.Children {
+			continue
+		}
+		if covers {
+			depth++
+			continue
+		}
+		r.SkipChildren()
+	}
+	return name, found
+}
+
+func (b *Binary) covers(e *dwarf.Entry, pc uint64) bool {
+	ranges, err := b.dwarf.Ranges(e)
+	if err != nil {
+		return false
+	}
+	for _, rg := range ranges {
+		if rg[0] <= pc && pc < rg[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Binary) originName(e *dwarf.Entry) string {
+	off, ok := e.Val(dwarf.AttrAbstractOrigin).(dwarf.Offset)
+	if !ok {
+		return ""
+	}
+	r := b.dwarf.Reader()
+	r.Seek(off)
+	origin, err := r.Next()
+	if err != nil || origin == nil {
+		return ""
+	}
+	name, _ := origin.Val(dwarf.AttrName).(string)
+	return name
+}
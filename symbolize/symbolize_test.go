@@ -0,0 +1,134 @@
+package symbolize
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/ice-coldbell/errorx/rawstack"
+)
+
+func openSelf(t *testing.T) *Binary {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("test binary is not ELF")
+	}
+	name, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+// buildRawStack builds testdata/rawstack with DWARF and returns the binary,
+// the raw stack it captured and the frames the runtime resolved for it.
+func buildRawStack(t *testing.T) (string, rawstack.Stack, []Frame) {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("binary is not ELF")
+	}
+	if testing.Short() {
+		t.Skip("builds a binary")
+	}
+	bin := filepath.Join(t.TempDir(), "rawstack")
+	if out, err := exec.Command("go", "build", "-o", bin, "./testdata/rawstack").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	out, err := exec.Command(bin).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Raw  rawstack.Stack
+		Want []Frame
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatal(err)
+	}
+	return bin, v.Raw, v.Want
+}
+
+func TestBinary_Symbolize(t *testing.T) {
+	bin, raw, want := buildRawStack(t)
+	b, err := Open(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if raw.BuildID != b.BuildID() {
+		t.Fatalf("rawstack.Stack.BuildID = %q, want %q", raw.BuildID, b.BuildID())
+	}
+
+	got, err := b.Symbolize(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Symbolize() = %+v, want %+v", got, want)
+	}
+	inlined := 0
+	for i := range want {
+		if got[i].Function != want[i].Function || got[i].File != want[i].File || got[i].Line != want[i].Line {
+			t.Errorf("Symbolize()[%d] = %s %s:%d, want %s %s:%d",
+				i, got[i].Function, got[i].File, got[i].Line, want[i].Function, want[i].File, want[i].Line)
+		}
+		if got[i].Inlined {
+			inlined++
+			if got[i].Function != "main.inlined" {
+				t.Errorf("Symbolize()[%d] = %s marked inlined", i, got[i].Function)
+			}
+		}
+	}
+	if inlined != 1 {
+		t.Errorf("Symbolize() has %d inlined frames, want 1", inlined)
+	}
+}
+
+func TestBinary_SymbolizeMismatch(t *testing.T) {
+	b := openSelf(t)
+	raw := rawstack.Of(errorx.New("test"))
+
+	tests := []struct {
+		name   string
+		modify func(*rawstack.Stack)
+	}{
+		{name: "build ID", modify: func(r *rawstack.Stack) { r.BuildID = "other" }},
+		{name: "unknown anchor", modify: func(r *rawstack.Stack) { r.AnchorFunc = "main.missing" }},
+		{name: "unaligned base", modify: func(r *rawstack.Stack) { r.Anchor += 3 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := raw
+			tt.modify(&r)
+			if _, err := b.Symbolize(r); err == nil {
+				t.Error("Symbolize() error = nil, want error")
+			}
+		})
+	}
+
+	shifted := raw
+	shifted.Anchor += 0x10000
+	shifted.PCs = nil
+	for _, pc := range raw.PCs {
+		shifted.PCs = append(shifted.PCs, pc+0x10000)
+	}
+	want, _ := b.Symbolize(raw)
+	got, err := b.Symbolize(shifted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if got[i].Function != want[i].Function || got[i].Line != want[i].Line {
+			t.Errorf("Symbolize() with load offset [%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Command rawstack prints a raw stack together with the frames the runtime
+// resolves for it, for testing symbolization against a binary with DWARF.
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+
+	"github.com/ice-coldbell/errorx"
+	"github.com/ice-coldbell/errorx/rawstack"
+)
+
+type frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+//go:noinline
+func newError() error {
+	return inlined()
+}
+
+func inlined() error {
+	return capture()
+}
+
+//go:noinline
+func capture() error {
+	return errorx.New("test")
+}
+
+func main() {
+	raw := rawstack.Of(newError())
+	var want []frame
+	frames := runtime.CallersFrames(raw.PCs)
+	for {
+		f, more := frames.Next()
+		want = append(want, frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+	json.NewEncoder(os.Stdout).Encode(map[string]any{"raw": raw, "want": want})
+}
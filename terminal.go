@@ -0,0 +1,209 @@
+package errorx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ColorMode selects whether RenderTerminal uses ANSI colors.
+type ColorMode int
+
+const (
+	// ColorAuto uses colors when the writer is a terminal and NO_COLOR is
+	// not set.
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// TerminalOptions configures RenderTerminal.
+type TerminalOptions struct {
+	Color ColorMode
+	// Width is the column at which text is wrapped. Zero uses the COLUMNS
+	// environment variable, or 80.
+	Width int
+	// Stack prints the stack of every error in the tree.
+	Stack bool
+}
+
+// RenderTerminal writes err for display in a terminal: the message, its
+// code and hints, then its causes and joined errors as a tree.
+//
+//	error: load config: open app.yaml: no such file
+//	│ code: config_missing
+//	│ hint: run "app init" to create a config file
+//	└─ open app.yaml: no such file
+func RenderTerminal(w io.Writer, err error, opts TerminalOptions) error {
+	if err == nil {
+		return nil
+	}
+	t := &terminal{
+		color: opts.useColor(w),
+		width: opts.lineWidth(),
+		stack: opts.Stack,
+	}
+	t.node(err, "", "", nil)
+	_, werr := io.WriteString(w, t.b.String())
+	return werr
+}
+
+func (opts TerminalOptions) useColor(w io.Writer) bool {
+	switch opts.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if osGetenv("NO_COLOR") != "" || osGetenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (opts TerminalOptions) lineWidth() int {
+	if opts.Width > 0 {
+		return opts.Width
+	}
+	if n, err := strconv.Atoi(osGetenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[1;31m"
+	ansiYellow = "\x1b[33m"
+	ansiDim    = "\x1b[2m"
+)
+
+type terminal struct {
+	b     strings.Builder
+	color bool
+	width int
+	stack bool
+}
+
+// node writes err and its children. branch prefixes the message line and
+// indent every other line of the node. prev is the stack printed by the
+// enclosing error.
+func (t *terminal) node(err error, branch, indent string, prev stack) {
+	var children []error
+	var e *customError
+	message := err.Error()
+	switch err := err.(type) {
+	case *customError:
+		e = err
+		if cause := nextCustom(err.err); cause != nil {
+			children = []error{cause}
+		}
+	case customErrors:
+		message = fmt.Sprintf("%d errors", len(err))
+		for _, member := range err {
+			children = append(children, member)
+		}
+	default:
+		if cause := nextCustom(err); cause != nil {
+			children = []error{cause}
+		}
+	}
+
+	label, style := "", ansiBold
+	if branch == "" && indent == "" {
+		label, style = "error: ", ""
+	}
+	t.line(branch, indent, label, ansiRed, message, style)
+
+	details := indent + "  "
+	if len(children) > 0 {
+		details = indent + "│ "
+	}
+	if e != nil {
+		if e.code != "" {
+			t.line(details, details, "code: ", ansiDim, e.code, "")
+		}
+		if e.hint != "" {
+			t.line(details, details, "hint: ", ansiYellow, e.hint, "")
+		}
+		if st := e.stack.filtered(); len(st) > 0 {
+			if t.stack {
+				common := commonSuffix(st, prev)
+				for _, f := range st[:len(st)-common] {
+					t.line(details, details, "at ", ansiDim, fmt.Sprintf("%s (%v)", f.function, f), ansiDim)
+				}
+				if common > 0 {
+					t.line(details, details, "", "", inCommon(common), ansiDim)
+				}
+			}
+			prev = st
+		}
+	}
+
+	for i, child := range children {
+		if i == len(children)-1 {
+			t.node(child, indent+"└─ ", indent+"   ", prev)
+		} else {
+			t.node(child, indent+"├─ ", indent+"│  ", prev)
+		}
+	}
+}
+
+// line writes text wrapped to the terminal width. The first line starts
+// with prefix and label, the following ones with indent and spaces under
+// the label.
+func (t *terminal) line(prefix, indent, label, labelStyle, text, textStyle string) {
+	lines := wrap(text, t.width-utf8.RuneCountInString(prefix)-utf8.RuneCountInString(label))
+	pad := strings.Repeat(" ", utf8.RuneCountInString(label))
+	for i, l := range lines {
+		if i == 0 {
+			t.b.WriteString(t.paint(prefix, ansiDim))
+			t.b.WriteString(t.paint(label, labelStyle))
+		} else {
+			t.b.WriteString(t.paint(indent, ansiDim))
+			t.b.WriteString(pad)
+		}
+		t.b.WriteString(t.paint(l, textStyle))
+		t.b.WriteString("\n")
+	}
+}
+
+func (t *terminal) paint(s, style string) string {
+	if !t.color || style == "" || s == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+// wrap splits text into lines of at most width runes, breaking at spaces.
+// Words longer than width get a line of their own.
+func wrap(text string, width int) []string {
+	const minWidth = 20
+	width = max(width, minWidth)
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		var line string
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width:
+				lines = append(lines, line)
+				line = word
+			default:
+				line += " " + word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
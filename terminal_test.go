@@ -0,0 +1,183 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderTerminal(t *testing.T) {
+	app := frame{function: "example.com/app.load", file: "/app/load.go", line: 5}
+	handle := frame{function: "example.com/app.handle", file: "/app/handle.go", line: 11}
+	main := frame{function: "main.main", file: "/app/main.go", line: 3}
+
+	inner := &customError{err: errors.New("open app.yaml: no such file"), stack: stack{app, handle, main}, code: "config_missing"}
+	outer := &customError{
+		err:   fmt.Errorf("load config: %w", inner),
+		stack: stack{handle, main},
+		hint:  `run "app init" to create a config file`,
+	}
+	joined := Join(
+		&customError{err: errors.New("first"), code: "a"},
+		&customError{err: errors.New("second"), hint: "retry later"},
+	)
+
+	tests := []struct {
+		name string
+		err  error
+		opts TerminalOptions
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "std error",
+			err:  errors.New("boom"),
+			want: "error: boom\n",
+		},
+		{
+			name: "cause tree",
+			err:  outer,
+			want: "error: load config: open app.yaml: no such file\n" +
+				"│ hint: run \"app init\" to create a config file\n" +
+				"└─ open app.yaml: no such file\n" +
+				"     code: config_missing\n",
+		},
+		{
+			name: "stack",
+			err:  outer,
+			opts: TerminalOptions{Stack: true},
+			want: "error: load config: open app.yaml: no such file\n" +
+				"│ hint: run \"app init\" to create a config file\n" +
+				"│ at example.com/app.handle (handle.go:11)\n" +
+				"│ at main.main (main.go:3)\n" +
+				"└─ open app.yaml: no such file\n" +
+				"     code: config_missing\n" +
+				"     at example.com/app.load (load.go:5)\n" +
+				"     ... 2 frames in common\n",
+		},
+		{
+			name: "joined",
+			err:  fmt.Errorf("batch: %w", joined),
+			want: "error: batch: first\n" +
+				"       second\n" +
+				"└─ 2 errors\n" +
+				"   ├─ first\n" +
+				"   │    code: a\n" +
+				"   └─ second\n" +
+				"        hint: retry later\n",
+		},
+		{
+			name: "wrapped",
+			err:  &customError{err: errors.New("the quick brown fox jumps over the lazy dog"), hint: "one two three four five six seven eight"},
+			opts: TerminalOptions{Width: 30},
+			want: "error: the quick brown fox\n" +
+				"       jumps over the lazy dog\n" +
+				"  hint: one two three four\n" +
+				"        five six seven eight\n",
+		},
+		{
+			name: "color",
+			err:  &customError{err: errors.New("boom"), hint: "fix it"},
+			opts: TerminalOptions{Color: ColorAlways},
+			want: ansiRed + "error: " + ansiReset + "boom\n" +
+				ansiDim + "  " + ansiReset + ansiYellow + "hint: " + ansiReset + "fix it\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := RenderTerminal(&b, tt.err, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("RenderTerminal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalOptions(t *testing.T) {
+	t.Cleanup(func() { osGetenv = os.Getenv })
+	env := map[string]string{}
+	osGetenv = func(key string) string { return env[key] }
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tests := []struct {
+		name      string
+		opts      TerminalOptions
+		env       map[string]string
+		w         any
+		wantColor bool
+		wantWidth int
+	}{
+		{name: "builder", w: &strings.Builder{}, wantWidth: 80},
+		{name: "regular file", w: f, wantWidth: 80},
+		{name: "NO_COLOR", env: map[string]string{"NO_COLOR": "1"}, w: f, wantWidth: 80},
+		{name: "always", opts: TerminalOptions{Color: ColorAlways, Width: 40}, w: f, wantColor: true, wantWidth: 40},
+		{name: "never", opts: TerminalOptions{Color: ColorNever}, w: f, wantWidth: 80},
+		{name: "COLUMNS", env: map[string]string{"COLUMNS": "120"}, w: f, wantWidth: 120},
+		{name: "invalid COLUMNS", env: map[string]string{"COLUMNS": "wide"}, w: f, wantWidth: 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env = tt.env
+			w := tt.w.(interface{ Write([]byte) (int, error) })
+			if got := tt.opts.useColor(w); got != tt.wantColor {
+				t.Errorf("useColor() = %v, want %v", got, tt.wantColor)
+			}
+			if got := tt.opts.lineWidth(); got != tt.wantWidth {
+				t.Errorf("lineWidth() = %v, want %v", got, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func Test_wrap(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{text: "", width: 30, want: []string{""}},
+		{text: "short", width: 30, want: []string{"short"}},
+		{text: "a\nb", width: 30, want: []string{"a", "b"}},
+		{
+			text:  "aaaa bbbb cccc dddd eeee ffff",
+			width: 20,
+			want:  []string{"aaaa bbbb cccc dddd", "eeee ffff"},
+		},
+		{
+			text:  "x github.com/a/very/long/import/path/that/does/not/fit y",
+			width: 5,
+			want:  []string{"x", "github.com/a/very/long/import/path/that/does/not/fit", "y"},
+		},
+	}
+	for _, tt := range tests {
+		if got := wrap(tt.text, tt.width); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestHints(t *testing.T) {
+	inner := WithHint(New("inner"), "check the input")
+	err := WithHint(Wrap(fmt.Errorf("outer: %w", inner)), "try again")
+	if got, want := Hints(err), []string{"try again", "check the input"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Hints() = %q, want %q", got, want)
+	}
+	if got := Hints(errors.New("std")); got != nil {
+		t.Errorf("Hints(std) = %q, want nil", got)
+	}
+}
@@ -6,6 +6,10 @@ func Wrap(err error) CustomError {
 	return WrapDepth(err, 4)
 }
 
+// WrapDepth is Wrap for helpers that wrap errors on behalf of their caller.
+// depth is passed to runtime.Callers, where 2 is WrapDepth itself and 3 is
+// its caller, so 4 starts the stack at the caller of the function that calls
+// WrapDepth, as Wrap does. A CustomError is returned unchanged.
 func WrapDepth(err error, depth int) CustomError {
 	if err == nil {
 		return nil
@@ -20,6 +24,40 @@ func WrapDepth(err error, depth int) CustomError {
 	}
 }
 
+// WithMessage wraps err with a "message: err" prefix and records the stack of
+// the caller. It returns nil for a nil err.
+func WithMessage(err error, message string) CustomError {
+	if err == nil {
+		return nil
+	}
+	return &customError{
+		err:   &withMessage{message: message, err: err},
+		stack: callers(3),
+		data:  make(map[string]any),
+	}
+}
+
+// withMessage has a Cause method, unlike fmt.Errorf wrapping, so Cause walks
+// through it.
+type withMessage struct {
+	message string
+	err     error
+}
+
+func (w *withMessage) Error() string { return w.message + ": " + w.err.Error() }
+
+func (w *withMessage) Unwrap() error { return w.err }
+
+func (w *withMessage) Cause() error { return w.err }
+
+// custom returns err as a *customError for the package-level With
+// functions, wrapping it with the stack of their caller. It returns nil for
+// a nil err.
+func custom(err error) *customError {
+	e, _ := WrapDepth(err, 5).(*customError)
+	return e
+}
+
 func WrapWithData(err error, data map[string]any) CustomError {
 	return wrapWithData(err, data)
 }
@@ -38,6 +76,23 @@ func wrapWithData(err error, data map[string]any) CustomError {
 	}
 }
 
+// Cause returns the innermost error reached through Cause() error methods,
+// like Cause in github.com/pkg/errors. Unlike Unwrap, it stops at the first
+// error without a Cause method.
+func Cause(err error) error {
+	type causer interface {
+		Cause() error
+	}
+	for err != nil {
+		c, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+	return err
+}
+
 func Unwrap(err error) error {
 	return errors.Unwrap(err)
 }
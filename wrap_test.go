@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"os"
 	"reflect"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -360,6 +361,44 @@ func TestWrapWithCustomError(t *testing.T) {
 	assert.Equal(t, data, val.data[key])
 }
 
+func TestWithMessage(t *testing.T) {
+	defer func(f func(int, []uintptr) int) { runtimeCallers = f }(runtimeCallers)
+	runtimeCallers = runtime.Callers
+	err := errors.New("eof")
+	got := WithMessage(err, "read config")
+	assert.Equal(t, "read config: eof", got.Error())
+	assert.True(t, Is(got, err))
+	assert.Equal(t, "github.com/ice-coldbell/errorx.TestWithMessage", StackOf(got)[0].Function)
+
+	inner := WithCode(New("inner"), "c")
+	assert.Equal(t, "c", Code(WithMessage(inner, "outer")))
+
+	assert.Nil(t, WithMessage(nil, "read config"))
+}
+
+func TestCause(t *testing.T) {
+	root := errors.New("root")
+	wrapped := fmt.Errorf("wrapped: %w", New("inner"))
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "plain", err: root, want: root},
+		{name: "wrap", err: Wrap(root), want: root},
+		{name: "with message", err: WithMessage(Wrap(root), "outer"), want: root},
+		{name: "stops without Cause", err: Wrap(wrapped), want: wrapped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cause(tt.err); got != tt.want {
+				t.Errorf("Cause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWrapWithData(t *testing.T) {
 	const errMessage = "test wrap error"
 